@@ -0,0 +1,166 @@
+// File: cmd/bot/glossary.go
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"the-keeper/internal/bot"
+)
+
+const glossaryUsage = `Usage:
+  bot glossary export [-format yaml|csv|md] [-o file]
+  bot glossary import [-conflict skip|overwrite|rename] [-dry-run] <file>`
+
+// runGlossaryCommand exports or imports the glossary without connecting to
+// Discord, so it can be edited offline. It returns the exit code.
+func runGlossaryCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, glossaryUsage)
+		return 2
+	}
+	switch args[0] {
+	case "export":
+		return runGlossaryExport(args[1:])
+	case "import":
+		return runGlossaryImport(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, glossaryUsage)
+		return 2
+	}
+}
+
+func runGlossaryExport(args []string) int {
+	flags := flag.NewFlagSet("glossary export", flag.ContinueOnError)
+	formatName := flags.String("format", bot.GlossaryYAML, "file format: yaml, csv or md")
+	output := flags.String("o", "", "file to write, glossary.<format> by default")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	format, err := bot.ParseGlossaryFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if *output == "" {
+		*output = "glossary." + format
+	}
+
+	b, err := openGlossaryBot()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer b.Shutdown()
+
+	entries, err := b.GlossaryEntries()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading terms: %v\n", err)
+		return 1
+	}
+	data, err := bot.EncodeGlossary(entries, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding glossary: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", *output, err)
+		return 1
+	}
+	fmt.Printf("Exported %d terms to %s\n", len(entries), *output)
+	return 0
+}
+
+func runGlossaryImport(args []string) int {
+	flags := flag.NewFlagSet("glossary import", flag.ContinueOnError)
+	conflictName := flags.String("conflict", bot.ImportSkip, "what to do with titles that are taken: skip, overwrite or rename")
+	dryRun := flags.Bool("dry-run", false, "show what would be imported without changing anything")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, glossaryUsage)
+		return 2
+	}
+	filename := flags.Arg(0)
+	conflict, err := bot.ParseImportConflict(strings.ToLower(*conflictName))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	format, err := bot.GlossaryFormatOf(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", filename, err)
+		return 1
+	}
+	entries, err := bot.DecodeGlossary(data, format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	b, err := openGlossaryBot()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer b.Shutdown()
+
+	plan, err := b.PlanGlossaryImport(entries, conflict)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error planning import: %v\n", err)
+		return 1
+	}
+	for _, line := range plan.Lines() {
+		fmt.Println(line)
+	}
+	if *dryRun {
+		fmt.Printf("Dry run of %s: %s\n", filename, plan.Summary())
+		return 0
+	}
+
+	skipped, err := b.ImportGlossary(plan, filename, cliActor())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error importing %s, nothing was changed: %v\n", filename, err)
+		return 1
+	}
+	fmt.Printf("Imported %s: %s\n", filename, plan.Summary())
+	if len(skipped) > 0 {
+		fmt.Printf("Aliases and triggers that are invalid or already used were left out: %s\n", strings.Join(skipped, ", "))
+	}
+	return 0
+}
+
+// cliActor is recorded in the audit log for changes made from the command
+// line, named after the system user running it.
+func cliActor() bot.Actor {
+	actor := bot.Actor{Name: "cli"}
+	if u, err := user.Current(); err == nil {
+		actor.Name += ":" + u.Username
+	}
+	return actor
+}
+
+// openGlossaryBot opens the bot's database without connecting to Discord.
+func openGlossaryBot() (*bot.Bot, error) {
+	config, err := bot.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error loading config: %w", err)
+	}
+	config.Discord.Enabled = false
+	b, err := bot.NewBot(config, bot.InitializeLogger(config))
+	if err != nil {
+		return nil, fmt.Errorf("error creating bot: %w", err)
+	}
+	return b, nil
+}
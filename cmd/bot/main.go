@@ -3,6 +3,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -11,7 +12,7 @@ import (
 	"syscall"
 
 	"the-keeper/internal/bot"
-	_ "the-keeper/internal/bot/handlers" // Import handlers for deferred registration
+	"the-keeper/internal/bot/handlers"
 
 	"github.com/sirupsen/logrus"
 )
@@ -61,10 +62,24 @@ func performStartupChecks(b *bot.Bot) error {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "glossary" {
+		os.Exit(runGlossaryCommand(os.Args[2:]))
+	}
+
+	recordFixtures := flag.Bool("record-fixtures", false, "save every scraped response as a test fixture")
+	replayFixtures := flag.Bool("replay-fixtures", false, "scrape from the recorded fixtures instead of the network")
+	flag.Parse()
+
 	config, err := bot.LoadConfig()
 	if err != nil {
 		logrus.Fatalf("Error loading config: %v", err)
 	}
+	if *recordFixtures {
+		config.Scrape.Fixtures.Record = true
+	}
+	if *replayFixtures {
+		config.Scrape.Fixtures.Replay = true
+	}
 
 	fmt.Printf("Main: Discord Role ID from config: %s\n", config.Discord.RoleID)
 
@@ -88,7 +103,7 @@ func main() {
 	listDirectoryContents(currentDir, logger)
 	listDirectoryContents(filepath.Join(currentDir, "configs"), logger)
 
-	commandsYamlPath := filepath.Join("configs", "commands.yaml")
+	commandsYamlPath := config.Paths.CommandsConfig
 	if _, err := os.Stat(commandsYamlPath); os.IsNotExist(err) {
 		logger.Fatalf("commands.yaml not found at %s", commandsYamlPath)
 	}
@@ -99,10 +114,10 @@ func main() {
 		logger.Fatalf("Error creating bot: %v", err)
 	}
 
-	// Process all pending handler registrations after bot creation
-	discordBot.ProcessPendingRegistrations()
+	// Register all command handlers on this bot instance
+	handlers.RegisterHandlers(discordBot)
 
-	// Then load commands
+	// Then load commands, which the bot reloads by itself when the file changes
 	if err := discordBot.LoadCommands(commandsYamlPath); err != nil {
 		logger.Fatalf("Error loading commands: %v", err)
 	}
@@ -127,6 +142,9 @@ func main() {
 	// Start periodic scraping
 	discordBot.StartPeriodicScraping()
 
+	// Start the scheduled digests
+	discordBot.StartDigests()
+
 	http.HandleFunc("/", handleRoot)
 	http.HandleFunc("/healthz", handleHealthCheck)
 	http.HandleFunc("/oauth2/callback", handleOAuth2Callback(logger))
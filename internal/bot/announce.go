@@ -0,0 +1,66 @@
+// File: internal/bot/announce.go
+
+package bot
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RedeemComponent is the custom ID prefix of the "Redeem for me" button on
+// new code announcements. The button carries the code as its argument.
+const RedeemComponent = "redeem"
+
+// announcementColor is the embed color of new code announcements.
+const announcementColor = 0x2ecc71
+
+// notifyNewCodes sends a new_code notification for each code and DMs the
+// codes to members who opted in. Discord targets get the code as an embed
+// with a button that redeems the code for whoever presses it.
+func (b *Bot) notifyNewCodes(codes []DiscoveredCode) {
+	for _, code := range codes {
+		b.Notify(b.newCodeNotification(code))
+	}
+	b.dmNewCodes(codes)
+	b.GetLogger().WithField("code_count", len(codes)).Info("New gift codes notification sent")
+}
+
+func (b *Bot) newCodeNotification(code DiscoveredCode) Notification {
+	return Notification{
+		Event: EventNewCode,
+		Data:  code,
+		Embed: code.AnnouncementEmbed(),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Redeem for me",
+					Style:    discordgo.SuccessButton,
+					Emoji:    &discordgo.ComponentEmoji{Name: "🎁"},
+					CustomID: ComponentID(RedeemComponent, code.Code),
+				},
+			}},
+		},
+	}
+}
+
+// AnnouncementEmbed renders a newly announced code for the notification channel.
+func (c DiscoveredCode) AnnouncementEmbed() *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🎉 New gift code: %s", c.Code),
+		Color: announcementColor,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Code", Value: fmt.Sprintf("`%s`", c.Code), Inline: true},
+			{Name: "Confidence", Value: FormatConfidence(c.Confidence), Inline: true},
+			{Name: "First seen", Value: fmt.Sprintf("<t:%d:R>", c.FirstSeenAt.Unix()), Inline: true},
+			{Name: "Sources", Value: orNone(c.SourceList())},
+		},
+	}
+	if c.Description != "" {
+		embed.Description = Truncate(c.Description, 1024)
+	}
+	if c.SubmittedBy != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Submitted by", Value: fmt.Sprintf("<@%s>", c.SubmittedBy)})
+	}
+	return embed
+}
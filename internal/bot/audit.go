@@ -0,0 +1,136 @@
+// File: internal/bot/audit.go
+
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// auditQueryLimit caps how many events a single audit query returns.
+const auditQueryLimit = 500
+
+// Actor is who makes a change, as recorded in the audit log. Changes made
+// outside Discord, such as from the CLI, have no ID or guild.
+type Actor struct {
+	ID      string
+	Name    string
+	GuildID string
+}
+
+// MessageActor returns the author of a command message.
+func MessageActor(m *discordgo.MessageCreate) Actor {
+	return Actor{ID: m.Author.ID, Name: m.Author.Username, GuildID: m.GuildID}
+}
+
+// InteractionActor returns the user who pressed a button.
+func InteractionActor(i *discordgo.InteractionCreate) Actor {
+	actor := Actor{ID: InteractionUserID(i), GuildID: i.GuildID}
+	switch {
+	case i.Member != nil && i.Member.User != nil:
+		actor.Name = i.Member.User.Username
+	case i.User != nil:
+		actor.Name = i.User.Username
+	}
+	return actor
+}
+
+// audit records a change made by actor. It is called by the methods that
+// change data, so every command, button and CLI path that uses them is audited.
+func (b *Bot) audit(actor Actor, action, target, before, after string) {
+	b.RecordAudit(AuditEvent{
+		ActorID:   actor.ID,
+		ActorName: actor.Name,
+		GuildID:   actor.GuildID,
+		Action:    action,
+		Target:    target,
+		Before:    before,
+		After:     after,
+	})
+}
+
+// RecordAudit stores an audit event and mirrors it to the audit channel when one is configured.
+// Failures are logged rather than returned so that auditing never blocks the action itself.
+func (b *Bot) RecordAudit(event AuditEvent) {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	if err := b.DB.Create(&event).Error; err != nil {
+		b.GetLogger().WithError(err).WithField("action", event.Action).Error("Error recording audit event")
+	}
+
+	channelID := b.Config.Discord.AuditChannelID
+	if channelID == "" || b.Session == nil {
+		return
+	}
+	if _, err := b.Session.ChannelMessageSendEmbed(channelID, event.Embed()); err != nil {
+		b.GetLogger().WithError(err).Error("Error mirroring audit event to audit channel")
+	}
+}
+
+// ListAuditEvents returns the newest audit events of a guild, along with those
+// made outside any guild, optionally filtered by actor and action. Empty
+// filters match everything.
+func (b *Bot) ListAuditEvents(guildID, actorID, action string) ([]AuditEvent, error) {
+	query := b.DB.Where("guild_id = ? OR guild_id = ''", guildID)
+	if actorID != "" {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action != "" {
+		query = query.Where("action = ?", action)
+	}
+	var events []AuditEvent
+	err := query.Order("created_at desc").Limit(auditQueryLimit).Find(&events).Error
+	return events, err
+}
+
+// Summary renders the event as a single line for lists.
+func (e AuditEvent) Summary() string {
+	line := fmt.Sprintf("`%s` **%s** by %s", e.CreatedAt.UTC().Format("2006-01-02 15:04"), e.Action, e.actorMention())
+	if e.Target != "" {
+		line += fmt.Sprintf(" on `%s`", e.Target)
+	}
+	if e.Before != "" || e.After != "" {
+		line += fmt.Sprintf(": %s → %s", orNone(e.Before), orNone(e.After))
+	}
+	return line
+}
+
+// Embed renders the event for the audit channel.
+func (e AuditEvent) Embed() *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     e.Action,
+		Timestamp: e.CreatedAt.Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Actor", Value: e.actorMention(), Inline: true},
+		},
+	}
+	if e.Target != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Target", Value: e.Target, Inline: true})
+	}
+	if e.Before != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Before", Value: Truncate(e.Before, 1024)})
+	}
+	if e.After != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "After", Value: Truncate(e.After, 1024)})
+	}
+	return embed
+}
+
+// actorMention mentions the actor, or names it for changes made outside
+// Discord.
+func (e AuditEvent) actorMention() string {
+	if e.ActorID == "" {
+		return orNone(e.ActorName)
+	}
+	return fmt.Sprintf("<@%s>", e.ActorID)
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "_none_"
+	}
+	return Truncate(value, 100)
+}
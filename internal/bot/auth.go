@@ -41,9 +41,9 @@ func (b *Bot) IsAuthorized(s *discordgo.Session, guildID, userID string) bool {
 }
 
 // GetOAuth2URL generates the Discord OAuth2 authorization URL
-func GetOAuth2URL() string {
-	clientID := GetConfig().Discord.ClientID
-	redirectURI := GetConfig().Discord.RedirectURL
+func (b *Bot) GetOAuth2URL() string {
+	clientID := b.Config.Discord.ClientID
+	redirectURI := b.Config.Discord.RedirectURL
 	scopes := "identify guilds bot"
 
 	return fmt.Sprintf(
@@ -0,0 +1,313 @@
+// File: internal/bot/auto_reply.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// termReferencePattern matches terms referenced inline as [[term]].
+var termReferencePattern = regexp.MustCompile(`\[\[([^\[\]\n]{1,64})\]\]`)
+
+// AddTermTrigger makes a keyword explain a term in auto-reply channels.
+func (b *Bot) AddTermTrigger(term, keyword string, actor Actor) (Term, error) {
+	existingTerm, err := b.FindTerm(term)
+	if err != nil {
+		return existingTerm, err
+	}
+	keyword = NormalizeTermTitle(keyword)
+
+	existing, owner, err := b.FindTermTrigger(keyword)
+	if err == nil {
+		return existingTerm, fmt.Errorf("'%s' already triggers term '%s'", existing.Keyword, owner.Term)
+	}
+	if existing.ID != 0 {
+		// The keyword is taken even if its term couldn't be loaded
+		return existingTerm, err
+	}
+
+	if err := b.DB.Create(&TermTrigger{TermID: existingTerm.ID, Keyword: keyword}).Error; err != nil {
+		return existingTerm, fmt.Errorf("error saving trigger: %w", err)
+	}
+	b.invalidateTermTriggers()
+	b.audit(actor, "term.trigger", existingTerm.Term, "", keyword)
+	return existingTerm, nil
+}
+
+// FindTermTrigger looks up a trigger keyword, ignoring case, and returns the
+// term it belongs to.
+func (b *Bot) FindTermTrigger(keyword string) (TermTrigger, Term, error) {
+	var existing TermTrigger
+	var t Term
+	keyword = NormalizeTermTitle(keyword)
+	result := b.DB.Where("LOWER(keyword) = ?", strings.ToLower(keyword)).First(&existing)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return existing, t, fmt.Errorf("trigger '%s' not found", keyword)
+		}
+		return existing, t, result.Error
+	}
+	err := b.DB.Unscoped().First(&t, existing.TermID).Error
+	return existing, t, err
+}
+
+// RemoveTermTrigger removes a trigger keyword and returns the term it
+// belonged to.
+func (b *Bot) RemoveTermTrigger(keyword string, actor Actor) (Term, error) {
+	existing, t, err := b.FindTermTrigger(keyword)
+	if err != nil {
+		return t, err
+	}
+	if err := b.DB.Delete(&existing).Error; err != nil {
+		return t, fmt.Errorf("error removing trigger: %w", err)
+	}
+	b.invalidateTermTriggers()
+	b.audit(actor, "term.untrigger", t.Term, existing.Keyword, "")
+	return t, nil
+}
+
+// TermTriggers returns the trigger keywords of a term.
+func (b *Bot) TermTriggers(term string) (Term, []string, error) {
+	t, err := b.FindTerm(term)
+	if err != nil {
+		return t, nil, err
+	}
+	var keywords []string
+	err = b.DB.Model(&TermTrigger{}).Where("term_id = ?", t.ID).Order("keyword").Pluck("keyword", &keywords).Error
+	return t, keywords, err
+}
+
+// termTriggers returns every trigger keyword, loading them once and then
+// serving them from memory until a trigger is added, removed or imported.
+// Triggers of removed terms are included.
+func (b *Bot) termTriggers() ([]TermTrigger, error) {
+	b.triggersMutex.RLock()
+	triggers := b.triggers
+	b.triggersMutex.RUnlock()
+	if triggers != nil {
+		return triggers, nil
+	}
+
+	b.triggersMutex.Lock()
+	defer b.triggersMutex.Unlock()
+	if b.triggers == nil {
+		triggers = []TermTrigger{}
+		if err := b.DB.Find(&triggers).Error; err != nil {
+			return nil, fmt.Errorf("error loading term triggers: %w", err)
+		}
+		b.triggers = triggers
+	}
+	return b.triggers, nil
+}
+
+// invalidateTermTriggers makes the next match reload the trigger keywords.
+func (b *Bot) invalidateTermTriggers() {
+	b.triggersMutex.Lock()
+	b.triggers = nil
+	b.triggersMutex.Unlock()
+}
+
+// MatchTerms returns the terms a message references as [[term]], followed by
+// the terms whose trigger keywords it mentions, in the order they appear.
+// At most max_terms terms are looked up, as no more are explained at once.
+func (b *Bot) MatchTerms(content string) ([]Term, error) {
+	var terms []Term
+	seen := make(map[uint]bool)
+	add := func(t Term) {
+		if !seen[t.ID] {
+			seen[t.ID] = true
+			terms = append(terms, t)
+		}
+	}
+	limit := b.Config.AutoReply.MaxTerms
+
+	for _, match := range termReferencePattern.FindAllStringSubmatch(content, limit) {
+		t, err := b.FindTerm(match[1])
+		if errors.Is(err, ErrTermNotFound) {
+			continue
+		}
+		if err != nil {
+			return terms, err
+		}
+		add(t)
+	}
+
+	if len(terms) >= limit {
+		return terms, nil
+	}
+	triggers, err := b.termTriggers()
+	if err != nil {
+		return terms, err
+	}
+	type mention struct {
+		termID   uint
+		position int
+	}
+	var mentions []mention
+	lower := strings.ToLower(content)
+	for _, trigger := range triggers {
+		if position := keywordIndex(lower, strings.ToLower(trigger.Keyword)); position >= 0 {
+			mentions = append(mentions, mention{termID: trigger.TermID, position: position})
+		}
+	}
+	sort.SliceStable(mentions, func(i, j int) bool { return mentions[i].position < mentions[j].position })
+	for _, m := range mentions {
+		if len(terms) >= limit {
+			break
+		}
+		if seen[m.termID] {
+			continue
+		}
+		var t Term
+		err := b.DB.Preload("Aliases").First(&t, m.termID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// The term was removed; its triggers come back if it is restored
+			continue
+		}
+		if err != nil {
+			return terms, err
+		}
+		add(t)
+	}
+	return terms, nil
+}
+
+// keywordIndex returns the position of the first occurrence of keyword in
+// text as a whole word or phrase, or -1.
+func keywordIndex(text, keyword string) int {
+	if keyword == "" {
+		return -1
+	}
+	for start := 0; start < len(text); {
+		i := strings.Index(text[start:], keyword)
+		if i < 0 {
+			return -1
+		}
+		i += start
+		end := i + len(keyword)
+		before, _ := utf8.DecodeLastRuneInString(text[:i])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return i
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		start = i + size
+	}
+	return -1
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Embed renders a term for auto-replies.
+func (t Term) Embed() *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       t.Term,
+		Description: Truncate(t.Description, 4096),
+	}
+	footer := t.DisplayCategory()
+	if aliases := t.AliasList(); len(aliases) > 0 {
+		footer += " · also known as " + strings.Join(aliases, ", ")
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	return embed
+}
+
+func (b *Bot) autoReplyChannel(channelID string) bool {
+	for _, id := range b.Config.AutoReply.ChannelIDs {
+		if id == channelID {
+			return true
+		}
+	}
+	return false
+}
+
+// autoReplyRateLimited reports whether a channel got an auto-reply too
+// recently for another one, so that messages in a busy channel aren't
+// matched against the glossary for nothing.
+func (b *Bot) autoReplyRateLimited(channelID string) bool {
+	b.autoReplyMutex.Lock()
+	defer b.autoReplyMutex.Unlock()
+	_, found := b.autoReplies.Get("channel:" + channelID)
+	return found
+}
+
+// takeAutoReply returns the terms that may be explained in a channel now, at
+// most max_terms of them, and starts their cooldowns. It returns nothing while
+// the channel is rate limited.
+func (b *Bot) takeAutoReply(channelID string, terms []Term) []Term {
+	b.autoReplyMutex.Lock()
+	defer b.autoReplyMutex.Unlock()
+
+	channelKey := "channel:" + channelID
+	if _, found := b.autoReplies.Get(channelKey); found {
+		return nil
+	}
+	var due []Term
+	for _, t := range terms {
+		if len(due) == b.Config.AutoReply.MaxTerms {
+			break
+		}
+		if _, found := b.autoReplies.Get(fmt.Sprintf("term:%s:%d", channelID, t.ID)); !found {
+			due = append(due, t)
+		}
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	b.autoReplies.Set(channelKey, true, b.Config.AutoReply.ChannelInterval)
+	for _, t := range due {
+		b.autoReplies.Set(fmt.Sprintf("term:%s:%d", channelID, t.ID), true, b.Config.AutoReply.TermCooldown)
+	}
+	return due
+}
+
+// replyWithTerms explains the terms a message references as [[term]] or
+// mentions by trigger keyword, in the channels configured for auto-replies.
+func (b *Bot) replyWithTerms(s *discordgo.Session, m *discordgo.Message) {
+	if m.GuildID == "" || m.Author == nil || m.Author.Bot || !b.autoReplyChannel(m.ChannelID) {
+		return
+	}
+	if strings.HasPrefix(m.Content, b.Config.Discord.CommandPrefix) {
+		return
+	}
+	if b.autoReplyRateLimited(m.ChannelID) || !b.GetGuildSettings(m.GuildID).AutoRepliesEnabled {
+		return
+	}
+
+	terms, err := b.MatchTerms(m.Content)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("channel", m.ChannelID).Error("Error matching terms for auto-reply")
+		return
+	}
+	terms = b.takeAutoReply(m.ChannelID, terms)
+	if len(terms) == 0 {
+		return
+	}
+
+	// Discord caps the text of all embeds in a message at 6000 characters
+	embeds := make([]*discordgo.MessageEmbed, len(terms))
+	for i, t := range terms {
+		embeds[i] = t.Embed()
+		embeds[i].Description = Truncate(embeds[i].Description, 5000/len(terms))
+	}
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embeds:          embeds,
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("channel", m.ChannelID).Error("Error sending term auto-reply")
+	}
+}
@@ -0,0 +1,83 @@
+// File: internal/bot/auto_reply_test.go
+
+package bot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeywordIndex(t *testing.T) {
+	tests := []struct {
+		text, keyword string
+		want          int
+	}{
+		{"upgrade the furnace first", "furnace", 12},
+		{"furnaces are great", "furnace", -1},
+		{"the fc, then", "fc", 4},
+		{"fcs and fc", "fc", 8},
+		{"café fc", "fc", 6},
+		{"anything", "", -1},
+	}
+	for _, tt := range tests {
+		if got := keywordIndex(tt.text, tt.keyword); got != tt.want {
+			t.Errorf("keywordIndex(%q, %q) = %d, want %d", tt.text, tt.keyword, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTerms(t *testing.T) {
+	b := newTestBot(t)
+	b.Config.AutoReply.MaxTerms = 2
+	actor := Actor{ID: "editor"}
+	for _, term := range []string{"Furnace", "Frost Star", "Chief Gear", "Pet"} {
+		if err := b.AddTerm(term, term+".", actor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for term, keyword := range map[string]string{"Furnace": "furnace", "Frost Star": "fs", "Pet": "pets"} {
+		if _, err := b.AddTermTrigger(term, keyword, actor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.RemoveTerm("Pet", actor); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"nothing", "hello there", nil},
+		{"reference", "what is [[chief gear]]?", []string{"Chief Gear"}},
+		{"triggers in order", "fs or the furnace?", []string{"Frost Star", "Furnace"}},
+		{"references first", "furnace and [[Chief Gear]]", []string{"Chief Gear", "Furnace"}},
+		{"capped", "[[furnace]] [[frost star]] [[chief gear]]", []string{"Furnace", "Frost Star"}},
+		{"removed term", "pets and fs", []string{"Frost Star"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms, err := b.MatchTerms(tt.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, term := range terms {
+				got = append(got, term.Term)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MatchTerms(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+
+	// Triggers are cached, so adding one must show up in the next match
+	if _, err := b.AddTermTrigger("Chief Gear", "gear", actor); err != nil {
+		t.Fatal(err)
+	}
+	terms, err := b.MatchTerms("which gear?")
+	if err != nil || len(terms) != 1 || terms[0].Term != "Chief Gear" {
+		t.Errorf("MatchTerms after adding a trigger = %v, %v, want Chief Gear", terms, err)
+	}
+}
@@ -5,26 +5,40 @@ package bot
 import (
 	"context"
 	"fmt"
-	"path/filepath"
+	"net/http"
+	"slices"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 )
 
 // CommandHandler is the function type used to handle bot commands
 type CommandHandler func(*discordgo.Session, *discordgo.MessageCreate, []string, *Command)
 
-var instance *Bot
-var pendingHandlers = make(map[string]CommandHandler) // Moved declaration to the global scope
-
-func GetBot() *Bot {
-	return instance
-}
+// NewBot creates a bot with its own database connection and Discord session.
+// Handlers must be registered with RegisterHandler before LoadCommands is called.
+func NewBot(config *Config, logger *logrus.Logger) (*Bot, error) {
+	// Everything that can fail is set up before the database is opened, so
+	// that an error doesn't leave it open
+	channelWatches, err := compileChannelWatches(config.Scrape.Channels)
+	if err != nil {
+		return nil, err
+	}
+	for i, route := range config.Notifications.Routes {
+		if err := ValidateRoute(route); err != nil {
+			return nil, fmt.Errorf("notification route %d: %w", i+1, err)
+		}
+	}
 
-// File: internal/bot/bot.go
+	var session *discordgo.Session
+	if config.Discord.Enabled {
+		if session, err = discordgo.New("Bot " + config.Discord.Token); err != nil {
+			return nil, fmt.Errorf("error creating Discord session: %w", err)
+		}
+	}
 
-func NewBot(config *Config, logger *logrus.Logger) (*Bot, error) {
-	ctx, cancel := context.WithCancel(context.Background())
 	db, err := InitDB(config, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
@@ -33,51 +47,32 @@ func NewBot(config *Config, logger *logrus.Logger) (*Bot, error) {
 	// Log the salt value to verify it is loaded correctly
 	logger.Debugf("Loaded Gift Code Salt: %s", config.GiftCode.Salt)
 
+	ctx, cancel := context.WithCancel(context.Background())
 	bot := &Bot{
-		Config:          config,
-		DB:              db,
-		logger:          logger,
-		HandlerRegistry: make(map[string]CommandHandler),
-		ctx:             ctx,
-		cancel:          cancel,
-	}
-	if config.Discord.Enabled {
-		session, err := discordgo.New("Bot " + config.Discord.Token)
-		if err != nil {
-			return nil, fmt.Errorf("error creating Discord session: %w", err)
-		}
-		bot.Session = session
+		Config:            config,
+		DB:                db,
+		Session:           session,
+		logger:            logger,
+		HandlerRegistry:   make(map[string]CommandHandler),
+		componentHandlers: make(map[string]ComponentHandler),
+		commands:          make(map[string]*Command),
+		cooldowns:         cache.New(5*time.Minute, 10*time.Minute),
+		autoReplies:       cache.New(5*time.Minute, 10*time.Minute),
+		paginators:        cache.New(paginatorTTL, 10*time.Minute),
+		glossaryImports:   cache.New(glossaryImportTTL, 10*time.Minute),
+		scrapeClient:      &http.Client{},
+		webhookClient:     &http.Client{Timeout: config.Notifications.WebhookTimeout},
+		dms:               make(chan queuedDM, dmQueueSize),
+		channelWatches:    channelWatches,
+		termSearch:        setupTermSearch(db, logger),
+		ctx:               ctx,
+		cancel:            cancel,
 	}
+	bot.RegisterComponentHandler(paginatorComponent, bot.handlePaginatorComponent)
 
-	// Set the base URL for GiftCode after bot creation
-	SetGiftCodeBaseURL(config)
-
-	// Process pending registrations
-	bot.ProcessPendingRegistrations()
-
-	if err := bot.loadHandlers(); err != nil {
-		return nil, fmt.Errorf("failed to load handlers: %w", err)
-	}
-	instance = bot
 	return bot, nil
 }
 
-func (b *Bot) ProcessPendingRegistrations() {
-	b.GetLogger().Infof("Processing %d pending handler registrations", len(pendingHandlers))
-	for name, handler := range pendingHandlers {
-		b.RegisterHandler(name, handler)
-		b.GetLogger().Infof("Registered handler: %s", name)
-	}
-	b.GetLogger().Infof("HandlerRegistry now contains %d handlers", len(b.HandlerRegistry))
-	// Clear the pending handlers
-	pendingHandlers = make(map[string]CommandHandler)
-}
-
-func RegisterHandlerLater(name string, handler CommandHandler) {
-	pendingHandlers[name] = handler
-	logrus.Infof("Handler %s queued for registration", name)
-}
-
 func (b *Bot) RegisterHandler(name string, handler CommandHandler) {
 	b.HandlerRegistry[name] = handler
 	b.GetLogger().Debugf("Registered handler: %s", name)
@@ -85,42 +80,22 @@ func (b *Bot) RegisterHandler(name string, handler CommandHandler) {
 
 func (b *Bot) Start() error {
 	if b.Config.Discord.Enabled {
-		if err := InitDiscord(b.Config.Discord.Token, b.GetLogger()); err != nil {
+		if err := b.openDiscord(); err != nil {
 			return fmt.Errorf("failed to initialize Discord: %w", err)
 		}
-	}
-	if err := LoadCommands(b.Config.Paths.CommandsConfig, b.GetLogger(), b.HandlerRegistry); err != nil {
-		return fmt.Errorf("failed to load commands: %w", err)
+		go b.deliverDMs()
 	}
 	b.GetLogger().Info("Bot has been started")
 	return nil
 }
 
-func (b *Bot) loadHandlers() error {
-	handlersDir := "./internal/bot/handlers"
-	files, err := filepath.Glob(filepath.Join(handlersDir, "*.go"))
-	if err != nil {
-		return fmt.Errorf("failed to read handlers directory: %w", err)
-	}
-	for _, file := range files {
-		b.GetLogger().Infof("Loading handler file: %s", file)
-	}
-	// The actual loading of handlers now happens through the pending registrations
-	// processed in NewBot, so we don't need to do anything else here.
-	return nil
-}
-
 func (b *Bot) GetHandlerRegistry() map[string]CommandHandler {
 	return b.HandlerRegistry
 }
 
-func (b *Bot) LoadCommands(configPath string) error {
-	return LoadCommands(configPath, b.GetLogger(), b.HandlerRegistry)
-}
-
 func (b *Bot) Shutdown() error {
 	b.cancel() // Cancel the context to stop all goroutines
-	if b.Config.Discord.Enabled {
+	if b.Session != nil {
 		if err := b.Session.Close(); err != nil {
 			b.GetLogger().WithError(err).Error("Error closing Discord session")
 		}
@@ -141,30 +116,41 @@ func (b *Bot) messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 		return
 	}
 	b.GetLogger().Debugf("Received message: %s from user: %s", m.Content, m.Author.Username)
-	err := LoadCommands(b.Config.Paths.CommandsConfig, b.GetLogger(), b.HandlerRegistry)
-	if err != nil {
-		b.GetLogger().Errorf("Failed to load command config: %v", err)
-		return
-	}
-	HandleCommand(s, m, b.Config)
+	b.watchChannelMessage(m.Message)
+	b.replyWithTerms(s, m.Message)
+	b.reloadCommands()
+	b.HandleCommand(s, m)
 }
 
 func (b *Bot) IsAdmin(s *discordgo.Session, guildID, userID string) bool {
-	member, err := s.GuildMember(guildID, userID)
+	return b.HasRole(s, guildID, userID, b.Config.Discord.RoleID)
+}
+
+// HasRole checks if a user has a specific role
+func (b *Bot) HasRole(s *discordgo.Session, guildID, userID, roleID string) bool {
+	return slices.Contains(b.MemberRoles(s, guildID, userID), roleID)
+}
+
+// MemberRoles returns the role IDs of a guild member, from the state cache
+// when it has the member. It returns nil if the member can't be fetched.
+func (b *Bot) MemberRoles(s *discordgo.Session, guildID, userID string) []string {
+	member, err := s.State.Member(guildID, userID)
 	if err != nil {
-		b.GetLogger().Errorf("Error fetching guild member: %v", err)
-		return false
-	}
-	for _, roleID := range member.Roles {
-		if roleID == b.Config.Discord.RoleID {
-			return true
+		member, err = s.GuildMember(guildID, userID)
+		if err != nil {
+			b.GetLogger().Errorf("Error fetching guild member: %v", err)
+			return nil
 		}
 	}
-	return false
+	return member.Roles
 }
 
+// SendMessage is a helper function to send a message to a channel
 func (b *Bot) SendMessage(s *discordgo.Session, channelID, content string) error {
 	_, err := s.ChannelMessageSend(channelID, content)
+	if err != nil {
+		b.GetLogger().Errorf("Error sending message: %v", err)
+	}
 	return err
 }
 
@@ -172,13 +158,7 @@ func (b *Bot) GetLogger() *logrus.Logger {
 	return b.logger
 }
 
-/*
-// Session Management Upgrade (Commented Out)
-func (b *Bot) SetSession(session *discordgo.Session) {
-	b.Session = session
-}
-
-func (b *Bot) GetSession() *discordgo.Session {
-	return b.Session
+// Context returns the bot's lifetime context, cancelled on Shutdown.
+func (b *Bot) Context() context.Context {
+	return b.ctx
 }
-*/
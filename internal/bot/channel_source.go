@@ -0,0 +1,126 @@
+// File: internal/bot/channel_source.go
+
+package bot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// defaultChannelPattern matches codes announced as e.g. "Code: ABC123" or "CDK ABC123".
+const defaultChannelPattern = `(?i)\b(?:gift\s*code|code|cdk)s?\b\W{0,3}([A-Za-z0-9]{4,20})\b`
+
+// channelWatch is a compiled watched-channel source.
+type channelWatch struct {
+	source  ScrapeChannel
+	pattern *regexp.Regexp
+}
+
+// compileChannelWatches indexes the configured channel sources by channel ID.
+func compileChannelWatches(channels []ScrapeChannel) (map[string]*channelWatch, error) {
+	watches := make(map[string]*channelWatch)
+	for _, source := range channels {
+		expr := source.Pattern
+		if expr == "" {
+			expr = defaultChannelPattern
+		}
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("channel source %s: invalid pattern: %w", source.Name, err)
+		}
+		for _, channelID := range source.ChannelIDs {
+			watches[channelID] = &channelWatch{source: source, pattern: pattern}
+		}
+	}
+	return watches, nil
+}
+
+// Site returns the channel source as a scrape site, so that it shares the
+// code filtering and trust settings of scraped sites.
+func (c ScrapeChannel) Site() ScrapeSite {
+	return ScrapeSite{
+		Name:            c.Name,
+		Type:            SourceTypeRegex,
+		Pattern:         c.Pattern,
+		CodePattern:     c.CodePattern,
+		CodeCase:        c.CodeCase,
+		StripWhitespace: c.StripWhitespace,
+		Trust:           c.Trust,
+	}
+}
+
+// FindChannelSource looks up a configured channel source by name, ignoring case.
+func (b *Bot) FindChannelSource(name string) (ScrapeChannel, bool) {
+	for _, source := range b.Config.Scrape.Channels {
+		if strings.EqualFold(source.Name, name) {
+			return source, true
+		}
+	}
+	return ScrapeChannel{}, false
+}
+
+// watchChannelMessage extracts candidate codes from a message posted in a
+// watched channel, including messages crossposted from followed announcement
+// channels, and feeds them into new-code detection.
+func (b *Bot) watchChannelMessage(m *discordgo.Message) {
+	watch, exists := b.channelWatches[m.ChannelID]
+	if !exists {
+		return
+	}
+
+	candidates := extractMessageCodes(watch, m)
+	if len(candidates) == 0 {
+		return
+	}
+	codes, rejected, err := b.filterCodes(watch.source.Site(), candidates)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("source", watch.source.Name).Error("Error filtering channel codes")
+		return
+	}
+	b.GetLogger().WithField("source", watch.source.Name).
+		WithField("crosspost", m.Flags&discordgo.MessageFlagsIsCrossPosted != 0).
+		Infof("Found %d candidate codes in watched channel (%d rejected)", len(codes), rejected)
+	if len(codes) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(b.ctx, scrapeRunTimeout)
+		defer cancel()
+		b.processDiscoveredCodes(ctx, []ScrapeResult{{
+			SiteName: watch.source.Name,
+			Status:   ScrapeStatusChanged,
+			Codes:    codes,
+			Rejected: rejected,
+		}})
+	}()
+}
+
+// extractMessageCodes runs a channel's pattern over a message's content and
+// embeds. The embed title, or the first line of the message, becomes the description.
+func extractMessageCodes(watch *channelWatch, m *discordgo.Message) []GiftCode {
+	texts := []string{m.Content}
+	description, _, _ := strings.Cut(strings.TrimSpace(m.Content), "\n")
+	for _, embed := range m.Embeds {
+		texts = append(texts, embed.Title, embed.Description)
+		for _, field := range embed.Fields {
+			texts = append(texts, field.Name, field.Value)
+		}
+		if embed.Footer != nil {
+			texts = append(texts, embed.Footer.Text)
+		}
+		if description == "" {
+			description = embed.Title
+		}
+	}
+
+	var codes []GiftCode
+	for _, code := range matchCodes(watch.pattern, strings.Join(texts, "\n")) {
+		codes = append(codes, GiftCode{Code: code, Description: description, Source: watch.source.Name})
+	}
+	return codes
+}
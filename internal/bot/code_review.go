@@ -0,0 +1,168 @@
+// File: internal/bot/code_review.go
+
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// CodeReviewComponent is the custom ID prefix of the review buttons posted to
+// the admin channel. The buttons carry the action and the code as arguments.
+const CodeReviewComponent = "codereview"
+
+// Code review button actions
+const (
+	CodeReviewApprove = "approve"
+	CodeReviewDeploy  = "deploy"
+	CodeReviewReject  = "reject"
+)
+
+// submissionSource is the source recorded for codes submitted by members.
+const submissionSource = "Member submission"
+
+// SubmitGiftCode queues a code submitted by a member for admin review. The
+// code must match the gift code format and must not be rejected by the gift
+// code API for the submitter's player. Checking a code redeems it for the
+// submitter. Codes the submitter has already claimed count as valid, since
+// they had to exist to be claimed.
+func (b *Bot) SubmitGiftCode(ctx context.Context, code, description, playerID, channelID string, submitter Actor) (DiscoveredCode, error) {
+	pattern, err := b.codePattern(ScrapeSite{})
+	if err != nil {
+		return DiscoveredCode{}, err
+	}
+	if !pattern.MatchString(code) {
+		return DiscoveredCode{}, fmt.Errorf("'%s' doesn't look like a gift code (%d to %d letters and digits)",
+			code, b.Config.GiftCode.MinLength, b.Config.GiftCode.MaxLength)
+	}
+
+	// The code is checked against the API without holding the scrape lock, so
+	// whether it is known is checked again once the lock is taken
+	if record, err := b.loadNewSubmission(code); err != nil {
+		return record, err
+	}
+	check := DiscoveredCode{Code: code}
+	b.validateDiscoveredCode(ctx, &check, playerID)
+	if check.Validation == CodeValidationInvalid {
+		return check, fmt.Errorf("the gift code API rejected '%s': %s", check.Code, check.ValidationMessage)
+	}
+
+	b.scrapeMutex.Lock()
+	defer b.scrapeMutex.Unlock()
+
+	record, err := b.loadNewSubmission(code)
+	if err != nil {
+		return record, err
+	}
+	record.Validation = check.Validation
+	record.ValidationMessage = check.ValidationMessage
+
+	record.Description = description
+	record.Sources = submissionSource
+	record.Status = CodeStatusPending
+	record.SubmittedBy = submitter.ID
+	record.SubmitChannelID = channelID
+	record.FirstSeenAt = time.Now()
+	record.Confidence = b.codeConfidence(record)
+	if err := b.DB.Save(&record).Error; err != nil {
+		return record, fmt.Errorf("error saving submission: %w", err)
+	}
+	b.audit(submitter, "giftcode.submit", record.Code, "", record.Status)
+
+	b.sendCodeReview(record)
+	return record, nil
+}
+
+// loadNewSubmission returns a new record for a submitted code, or an error if
+// the code is already known.
+func (b *Bot) loadNewSubmission(code string) (DiscoveredCode, error) {
+	record, isNew, err := b.loadDiscoveredCode(code)
+	if err != nil {
+		return record, fmt.Errorf("error loading gift code: %w", err)
+	}
+	if !isNew {
+		switch record.Status {
+		case CodeStatusPending:
+			return record, fmt.Errorf("code '%s' is already waiting for review", record.Code)
+		default:
+			return record, fmt.Errorf("code '%s' is already known and was %s", record.Code, record.Status)
+		}
+	}
+	return record, nil
+}
+
+// sendCodeReview posts a pending code with approve, deploy and reject buttons
+// to the admin channel.
+func (b *Bot) sendCodeReview(record DiscoveredCode) {
+	b.GetLogger().WithField("code", record.Code).Info("Gift code queued for review")
+	channelID := b.Config.Discord.AdminChannelID
+	if channelID == "" || b.Session == nil {
+		return
+	}
+
+	_, err := b.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{record.ReviewEmbed()},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Approve",
+					Style:    discordgo.SuccessButton,
+					CustomID: ComponentID(CodeReviewComponent, CodeReviewApprove, record.Code),
+				},
+				discordgo.Button{
+					Label:    "Approve & deploy",
+					Style:    discordgo.PrimaryButton,
+					CustomID: ComponentID(CodeReviewComponent, CodeReviewDeploy, record.Code),
+				},
+				discordgo.Button{
+					Label:    "Reject",
+					Style:    discordgo.DangerButton,
+					CustomID: ComponentID(CodeReviewComponent, CodeReviewReject, record.Code),
+				},
+			}},
+		},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("code", record.Code).Error("Error posting gift code review")
+	}
+}
+
+// ReviewEmbed renders a discovered code for the review queue.
+func (c DiscoveredCode) ReviewEmbed() *discordgo.MessageEmbed {
+	validation := c.Validation
+	if validation == "" {
+		validation = "not checked"
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:     "🔎 Gift code awaiting review",
+		Timestamp: c.FirstSeenAt.Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Code", Value: fmt.Sprintf("`%s`", c.Code), Inline: true},
+			{Name: "Confidence", Value: FormatConfidence(c.Confidence), Inline: true},
+			{Name: "Validation", Value: validation, Inline: true},
+			{Name: "Sources", Value: orNone(c.SourceList())},
+		},
+	}
+	if c.Description != "" {
+		embed.Description = Truncate(c.Description, 1024)
+	}
+	if c.SubmittedBy != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Submitted by", Value: fmt.Sprintf("<@%s>", c.SubmittedBy)})
+	}
+	return embed
+}
+
+// creditSubmitter tells the member who submitted a code how the review went.
+func (b *Bot) creditSubmitter(record DiscoveredCode) {
+	if record.SubmittedBy == "" || record.SubmitChannelID == "" || b.Session == nil {
+		return
+	}
+	message := fmt.Sprintf("<@%s> your gift code `%s` was not accepted.", record.SubmittedBy, record.Code)
+	if record.Status == CodeStatusAnnounced {
+		message = fmt.Sprintf("🎉 <@%s> thanks! Your gift code `%s` was approved and announced.", record.SubmittedBy, record.Code)
+	}
+	b.SendMessage(b.Session, record.SubmitChannelID, message)
+}
@@ -4,35 +4,41 @@ package bot
 
 import (
 	"fmt"
-	"io/ioutil"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
 
-var CommandRegistry map[string]*Command
-
-func LoadCommands(configPath string, logger *logrus.Logger, handlerRegistry map[string]CommandHandler) error {
-	data, err := ioutil.ReadFile(configPath)
+// LoadCommands reads the command definitions and binds them to registered handlers.
+// The previous command set is replaced atomically so running handlers are unaffected.
+func (b *Bot) LoadCommands(configPath string) error {
+	logger := b.GetLogger()
+	info, err := os.Stat(configPath)
+	if err != nil {
+		return fmt.Errorf("error reading command config file: %w", err)
+	}
+	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return fmt.Errorf("error reading command config file: %w", err)
 	}
 	var config struct {
-		Prefix   string
-		Commands map[string]*Command
+		Prefix     string
+		Categories []*CommandCategory
+		Commands   map[string]*Command
 	}
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
 		return fmt.Errorf("error parsing command config: %w", err)
 	}
-	CommandRegistry = config.Commands
-	logger.Infof("Loaded %d commands from config", len(CommandRegistry))
+	logger.Infof("Loaded %d commands from config", len(config.Commands))
 
-	for name, cmd := range CommandRegistry {
+	for name, cmd := range config.Commands {
 		cmd.Name = name
-		if handler, ok := handlerRegistry[cmd.Handler]; ok {
+		if handler, ok := b.HandlerRegistry[cmd.Handler]; ok {
 			cmd.HandlerFunc = handler
 			logger.Infof("Handler '%s' associated with command '%s'", cmd.Handler, name)
 		} else {
@@ -41,7 +47,7 @@ func LoadCommands(configPath string, logger *logrus.Logger, handlerRegistry map[
 
 		for subName, subCmd := range cmd.Subcommands {
 			subCmd.Name = subName
-			if handler, ok := handlerRegistry[subCmd.Handler]; ok {
+			if handler, ok := b.HandlerRegistry[subCmd.Handler]; ok {
 				subCmd.HandlerFunc = handler
 				logger.Infof("Handler '%s' associated with subcommand '%s' of '%s'", subCmd.Handler, subName, name)
 			} else {
@@ -50,12 +56,125 @@ func LoadCommands(configPath string, logger *logrus.Logger, handlerRegistry map[
 		}
 	}
 
+	b.commandsMutex.Lock()
+	b.commands = config.Commands
+	b.categories = config.Categories
+	b.commandsModTime = info.ModTime()
+	b.commandsMutex.Unlock()
+
 	logger.Info("Command loading completed")
 	return nil
 }
 
-func HandleCommand(s *discordgo.Session, m *discordgo.MessageCreate, config *Config) {
-	content := strings.TrimPrefix(m.Content, config.Discord.CommandPrefix)
+// reloadCommands loads the command definitions again when the commands file
+// has changed since they were last loaded, so edits apply without a restart.
+// A file that fails to load is not retried until it changes again.
+func (b *Bot) reloadCommands() {
+	path := b.Config.Paths.CommandsConfig
+	info, err := os.Stat(path)
+	if err != nil {
+		b.GetLogger().Errorf("Failed to check command config: %v", err)
+		return
+	}
+
+	b.commandsMutex.Lock()
+	if info.ModTime().Equal(b.commandsModTime) {
+		b.commandsMutex.Unlock()
+		return
+	}
+	b.commandsModTime = info.ModTime()
+	b.commandsMutex.Unlock()
+
+	if err := b.LoadCommands(path); err != nil {
+		b.GetLogger().Errorf("Failed to reload command config: %v", err)
+	}
+}
+
+// Commands returns the currently loaded command set keyed by name.
+// The returned map must not be modified.
+func (b *Bot) Commands() map[string]*Command {
+	b.commandsMutex.RLock()
+	defer b.commandsMutex.RUnlock()
+	return b.commands
+}
+
+// Categories returns the help categories in the order they are declared.
+func (b *Bot) Categories() []*CommandCategory {
+	b.commandsMutex.RLock()
+	defer b.commandsMutex.RUnlock()
+	return b.categories
+}
+
+// SortedCommands returns the commands of a command map ordered by name.
+func SortedCommands(commands map[string]*Command) []*Command {
+	sorted := make([]*Command, 0, len(commands))
+	for _, cmd := range commands {
+		sorted = append(sorted, cmd)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// CanRun reports whether a user is allowed to run a command.
+// Commands with permission "admin" require the configured admin role.
+func (b *Bot) CanRun(s *discordgo.Session, guildID, userID string, cmd *Command) bool {
+	if cmd.Permission == "" || cmd.Permission == "everyone" {
+		return true
+	}
+	return b.CanRunWithRoles(b.MemberRoles(s, guildID, userID), cmd)
+}
+
+// CanRunWithRoles reports whether a member with the given roles is allowed to
+// run a command. Callers checking many commands look the roles up once with
+// MemberRoles instead of calling CanRun for each.
+func (b *Bot) CanRunWithRoles(roles []string, cmd *Command) bool {
+	switch cmd.Permission {
+	case "", "everyone":
+		return true
+	case "admin":
+		return slices.Contains(roles, b.Config.Discord.RoleID)
+	default:
+		b.GetLogger().Warnf("Unknown permission '%s' on command '%s'", cmd.Permission, cmd.Name)
+		return false
+	}
+}
+
+// WithPrefix replaces the leading "!" used in commands.yaml with the configured command prefix.
+func (b *Bot) WithPrefix(text string) string {
+	return b.Config.Discord.CommandPrefix + strings.TrimPrefix(text, "!")
+}
+
+// GetCommand looks up a top-level command by name or alias.
+func (b *Bot) GetCommand(name string) (*Command, bool) {
+	return findCommand(b.Commands(), name)
+}
+
+// Subcommand looks up a subcommand of c by name or alias.
+func (c *Command) Subcommand(name string) (*Command, bool) {
+	return findCommand(c.Subcommands, name)
+}
+
+func findCommand(commands map[string]*Command, name string) (*Command, bool) {
+	if cmd, exists := commands[name]; exists {
+		return cmd, true
+	}
+	for _, cmd := range commands {
+		for _, alias := range cmd.Aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (b *Bot) HandleCommand(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !strings.HasPrefix(m.Content, b.Config.Discord.CommandPrefix) {
+		return
+	}
+	content := strings.TrimPrefix(m.Content, b.Config.Discord.CommandPrefix)
 	args := strings.Fields(content)
 
 	if len(args) == 0 {
@@ -63,19 +182,25 @@ func HandleCommand(s *discordgo.Session, m *discordgo.MessageCreate, config *Con
 	}
 
 	cmdName := strings.ToLower(args[0])
-	cmd, exists := CommandRegistry[cmdName]
+	cmd, exists := b.GetCommand(cmdName)
 
 	if !exists {
+		b.suggestUnknownCommand(s, m, cmdName)
+		return
+	}
+
+	if !b.CheckCooldown(m.Author.ID, cmd.Name, cmd.Cooldown) {
 		return
 	}
 
-	if !CheckCooldown(m.Author.ID, cmdName, cmd.Cooldown) {
+	if !b.CanRun(s, m.GuildID, m.Author.ID, cmd) {
+		b.SendMessage(s, m.ChannelID, "𐄂 You do not have permission to use this command.")
 		return
 	}
 
 	if cmd.HandlerFunc != nil {
 		cmd.HandlerFunc(s, m, args[1:], cmd)
 	} else {
-		SendMessage(s, m.ChannelID, fmt.Sprintf("Command '%s' is not implemented yet.", cmdName))
+		b.SendMessage(s, m.ChannelID, fmt.Sprintf("Command '%s' is not implemented yet.", cmdName))
 	}
 }
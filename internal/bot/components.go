@@ -0,0 +1,108 @@
+// File: internal/bot/components.go
+
+package bot
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// ComponentHandler handles a message component interaction (button press).
+// args holds the colon-separated parts of the custom ID after the prefix.
+type ComponentHandler func(*discordgo.Session, *discordgo.InteractionCreate, []string)
+
+// RegisterComponentHandler routes component interactions whose custom ID
+// starts with "<prefix>:" to the given handler.
+func (b *Bot) RegisterComponentHandler(prefix string, handler ComponentHandler) {
+	b.componentHandlers[prefix] = handler
+	b.GetLogger().Debugf("Registered component handler: %s", prefix)
+}
+
+// ComponentID builds a custom ID understood by the component router.
+func ComponentID(prefix string, args ...string) string {
+	return strings.Join(append([]string{prefix}, args...), ":")
+}
+
+// newComponentToken returns a random ID for the state that component custom
+// IDs refer to. Unlike a counter, it can't repeat after a restart, so buttons
+// left in old messages can't reach state created since.
+func newComponentToken() string {
+	return strconv.FormatUint(rand.Uint64(), 36)
+}
+
+func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type != discordgo.InteractionMessageComponent {
+		return
+	}
+
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	handler, ok := b.componentHandlers[parts[0]]
+	if !ok {
+		b.GetLogger().Warnf("No component handler for custom ID: %s", i.MessageComponentData().CustomID)
+		return
+	}
+	handler(s, i, parts[1:])
+}
+
+// InteractionUserID returns the ID of the user who triggered an interaction,
+// whether it happened in a guild or in a DM.
+func InteractionUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+	return ""
+}
+
+// RespondEphemeral answers an interaction with a message only the presser can see.
+func (b *Bot) RespondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Failed to respond to interaction")
+	}
+}
+
+// DeferEphemeral acknowledges an interaction with a "thinking" state visible
+// only to the presser. Follow up with EditInteractionResponse.
+func (b *Bot) DeferEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Failed to defer interaction")
+	}
+	return err
+}
+
+// FollowupEphemeral sends a message visible only to the presser after the
+// interaction was already acknowledged.
+func (b *Bot) FollowupEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+		Flags:   discordgo.MessageFlagsEphemeral,
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Failed to send interaction followup")
+	}
+}
+
+// EditInteractionResponse replaces the content of a deferred interaction response.
+func (b *Bot) EditInteractionResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content}); err != nil {
+		b.GetLogger().WithError(err).Error("Failed to edit interaction response")
+	}
+}
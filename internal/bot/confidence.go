@@ -0,0 +1,295 @@
+// File: internal/bot/confidence.go
+
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// codeValidationTimeout bounds a single gift code API validation.
+const codeValidationTimeout = 15 * time.Second
+
+// processDiscoveredCodes merges the codes of a scrape run into the discovered
+// codes table and scores them. Codes that reach the confidence threshold are
+// announced (and deployed, if enabled); new codes below it are queued for review.
+func (b *Bot) processDiscoveredCodes(ctx context.Context, results []ScrapeResult) {
+	merged := mergeScrapedCodes(results)
+	validated := b.validateScrapedCodes(ctx, merged)
+
+	b.scrapeMutex.Lock()
+	var announce []DiscoveredCode
+	for _, found := range merged {
+		record, isNew, err := b.loadDiscoveredCode(found.Code)
+		if err != nil {
+			b.GetLogger().WithError(err).WithField("code", found.Code).Error("Error loading discovered code")
+			continue
+		}
+		if isNew {
+			record.Description = found.Description
+			record.FirstSeenAt = time.Now()
+			record.Status = CodeStatusPending
+		}
+		record.Sources = joinSources(splitSources(record.Sources), found.sources)
+
+		if record.Status == CodeStatusPending {
+			if check, exists := validated[record.Code]; exists && record.Validation == "" {
+				record.Validation = check.Validation
+				record.ValidationMessage = check.ValidationMessage
+			}
+			record.Confidence = b.codeConfidence(record)
+			switch {
+			case record.Validation == CodeValidationInvalid:
+				record.Status = CodeStatusRejected
+				record.ReviewedBy = "gift code API"
+			case record.Confidence >= b.Config.Scrape.Confidence.Threshold:
+				record.Status = CodeStatusAnnounced
+				announce = append(announce, record)
+			case isNew:
+				b.sendCodeReview(record)
+			}
+		}
+
+		if err := b.DB.Save(&record).Error; err != nil {
+			b.GetLogger().WithError(err).WithField("code", record.Code).Error("Error saving discovered code")
+		}
+	}
+	b.scrapeMutex.Unlock()
+
+	b.announceCodes(ctx, announce)
+}
+
+// validateScrapedCodes checks the codes that still need it against the gift
+// code API for the configured player, keyed by code. It runs before the
+// scrape lock is taken, since every check can take up to
+// codeValidationTimeout.
+func (b *Bot) validateScrapedCodes(ctx context.Context, codes []*scrapedCode) map[string]DiscoveredCode {
+	playerID := b.Config.Scrape.Confidence.ValidatePlayerID
+	validated := make(map[string]DiscoveredCode)
+	if playerID == "" {
+		return validated
+	}
+	for _, found := range codes {
+		record, isNew, err := b.loadDiscoveredCode(found.Code)
+		if err != nil || record.Validation != "" || (!isNew && record.Status != CodeStatusPending) {
+			continue
+		}
+		check := DiscoveredCode{Code: found.Code}
+		b.validateDiscoveredCode(ctx, &check, playerID)
+		if check.Validation != "" {
+			validated[found.Code] = check
+		}
+	}
+	return validated
+}
+
+// scrapedCode is a code seen in one scrape run with every site that listed it.
+type scrapedCode struct {
+	GiftCode
+	sources []string
+}
+
+// mergeScrapedCodes groups the codes of all results by code, in the order they
+// were first seen.
+func mergeScrapedCodes(results []ScrapeResult) []*scrapedCode {
+	byCode := make(map[string]*scrapedCode)
+	var merged []*scrapedCode
+	for _, result := range results {
+		for _, code := range result.Codes {
+			found, exists := byCode[code.Code]
+			if !exists {
+				found = &scrapedCode{GiftCode: code}
+				byCode[code.Code] = found
+				merged = append(merged, found)
+			}
+			if found.Description == "" {
+				found.Description = code.Description
+			}
+			found.sources = append(found.sources, result.SiteName)
+		}
+	}
+	return merged
+}
+
+// codeConfidence scores a code from 0 to 1. Each listing site contributes its
+// trust independently (1 - ∏(1 - trust)), so several weak sources can add up
+// to a confident one. A successful API validation makes the code certain.
+func (b *Bot) codeConfidence(record DiscoveredCode) float64 {
+	switch record.Validation {
+	case CodeValidationValid:
+		return 1
+	case CodeValidationInvalid:
+		return 0
+	}
+	doubt := 1.0
+	for _, source := range splitSources(record.Sources) {
+		doubt *= 1 - b.sourceTrust(source)
+	}
+	return 1 - doubt
+}
+
+// sourceTrust returns the configured trust of a site or channel source, clamped to [0, 1].
+func (b *Bot) sourceTrust(siteName string) float64 {
+	trust := b.Config.Scrape.Confidence.DefaultTrust
+	if site, exists := b.FindScrapeSite(siteName); exists && site.Trust != 0 {
+		trust = site.Trust
+	} else if channel, exists := b.FindChannelSource(siteName); exists && channel.Trust != 0 {
+		trust = channel.Trust
+	}
+	return max(0, min(1, trust))
+}
+
+// validateDiscoveredCode checks a code against the gift code API for a player.
+// The API has no way to check a code without claiming it, so a valid code is
+// redeemed for the player. Codes the API reports as already claimed exist, so
+// they count as valid. Errors leave the code unvalidated.
+func (b *Bot) validateDiscoveredCode(ctx context.Context, record *DiscoveredCode, playerID string) {
+	if playerID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, codeValidationTimeout)
+	defer cancel()
+
+	if err := b.loginPlayer(ctx, playerID); err != nil {
+		b.GetLogger().WithError(err).WithField("code", record.Code).Warn("Error logging in to validate discovered code")
+		return
+	}
+	errCode, msg, err := b.checkGiftCode(ctx, record.Code, playerID)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("code", record.Code).Warn("Error validating discovered code")
+		return
+	}
+	switch errCode {
+	case giftCodeOK, giftCodeClaimed:
+		record.Validation = CodeValidationValid
+	case giftCodeNotFound, giftCodeExpired:
+		record.Validation = CodeValidationInvalid
+	default:
+		b.GetLogger().WithField("code", record.Code).Warnf("Unexpected validation result: %v", msg)
+		return
+	}
+	record.ValidationMessage = fmt.Sprintf("%v", msg)
+}
+
+// announceCodes notifies about confident codes and deploys them when auto-deploy is on.
+func (b *Bot) announceCodes(ctx context.Context, codes []DiscoveredCode) {
+	if len(codes) == 0 {
+		return
+	}
+	b.notifyNewCodes(codes)
+	if !b.Config.Scrape.Confidence.AutoDeploy {
+		return
+	}
+	for _, code := range codes {
+		b.deployInBackground(code.Code, autoDeployActor)
+	}
+}
+
+// autoDeployActor is recorded in the audit log for codes deployed without review.
+var autoDeployActor = Actor{Name: "auto-deploy"}
+
+// deployInBackground deploys a gift code to every player without blocking the caller.
+func (b *Bot) deployInBackground(code string, actor Actor) {
+	go func() {
+		succeeded, total, err := b.DeployGiftCode(code, actor, nil)
+		if err != nil {
+			b.GetLogger().WithError(err).WithField("code", code).Error("Error deploying gift code")
+			return
+		}
+		b.GetLogger().WithField("code", code).Infof("Deployed gift code: %d of %d players succeeded", succeeded, total)
+	}()
+}
+
+// ListPendingCodes returns the codes waiting for admin review, newest first.
+func (b *Bot) ListPendingCodes() ([]DiscoveredCode, error) {
+	var codes []DiscoveredCode
+	err := b.DB.Where("status = ?", CodeStatusPending).Order("first_seen_at desc").Find(&codes).Error
+	return codes, err
+}
+
+// ReviewDiscoveredCode approves or rejects a pending code. Approved codes are
+// announced like any other confident code and deployed if deploy is set.
+// Members who submitted the code are told the outcome.
+func (b *Bot) ReviewDiscoveredCode(ctx context.Context, code string, approve, deploy bool, reviewer Actor) (DiscoveredCode, error) {
+	b.scrapeMutex.Lock()
+	defer b.scrapeMutex.Unlock()
+
+	record, isNew, err := b.loadDiscoveredCode(code)
+	if err != nil {
+		return record, err
+	}
+	if isNew {
+		return record, fmt.Errorf("code '%s' has not been discovered", code)
+	}
+	if record.Status != CodeStatusPending {
+		return record, fmt.Errorf("code '%s' is already %s", record.Code, record.Status)
+	}
+
+	record.Status = CodeStatusRejected
+	if approve {
+		record.Status = CodeStatusAnnounced
+	}
+	record.ReviewedBy = reviewer.Name
+	if err := b.DB.Save(&record).Error; err != nil {
+		return record, fmt.Errorf("error saving code review: %w", err)
+	}
+	b.audit(reviewer, "giftcode.review", record.Code, CodeStatusPending, record.Status)
+	if approve {
+		b.announceCodes(ctx, []DiscoveredCode{record})
+		if deploy && !b.Config.Scrape.Confidence.AutoDeploy {
+			b.deployInBackground(record.Code, reviewer)
+		}
+	}
+	b.creditSubmitter(record)
+	return record, nil
+}
+
+// loadDiscoveredCode returns the stored record for a code, or a new one if the
+// code hasn't been seen before.
+func (b *Bot) loadDiscoveredCode(code string) (DiscoveredCode, bool, error) {
+	var record DiscoveredCode
+	err := b.DB.Where("code = ?", code).First(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return DiscoveredCode{Code: code}, true, nil
+	}
+	return record, false, err
+}
+
+// FormatConfidence renders a confidence score as a percentage.
+func FormatConfidence(confidence float64) string {
+	return fmt.Sprintf("%.0f%%", confidence*100)
+}
+
+// SourceList renders the sites listing a code for display.
+func (c DiscoveredCode) SourceList() string {
+	return strings.Join(splitSources(c.Sources), ", ")
+}
+
+func splitSources(sources string) []string {
+	if sources == "" {
+		return nil
+	}
+	return strings.Split(sources, ",")
+}
+
+// joinSources merges source lists into a sorted, de-duplicated list.
+func joinSources(lists ...[]string) string {
+	seen := make(map[string]bool)
+	var sources []string
+	for _, list := range lists {
+		for _, source := range list {
+			if !seen[source] {
+				seen[source] = true
+				sources = append(sources, source)
+			}
+		}
+	}
+	sort.Strings(sources)
+	return strings.Join(sources, ",")
+}
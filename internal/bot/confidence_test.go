@@ -0,0 +1,72 @@
+// File: internal/bot/confidence_test.go
+
+package bot
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCodeConfidence(t *testing.T) {
+	b := &Bot{Config: &Config{}}
+	b.Config.Scrape.Confidence.DefaultTrust = 0.5
+	b.Config.Scrape.Sites = []ScrapeSite{
+		{Name: "Official", Trust: 1},
+		{Name: "Fansite", Trust: 0.6},
+		{Name: "Forum"},
+		{Name: "Broken", Trust: 1.5},
+	}
+	b.Config.Scrape.Channels = []ScrapeChannel{
+		{Name: "Announcements", Trust: 0.9},
+	}
+
+	tests := []struct {
+		name   string
+		record DiscoveredCode
+		want   float64
+	}{
+		{"no sources", DiscoveredCode{}, 0},
+		{"trusted site", DiscoveredCode{Sources: "Official"}, 1},
+		{"one site", DiscoveredCode{Sources: "Fansite"}, 0.6},
+		{"default trust", DiscoveredCode{Sources: "Forum"}, 0.5},
+		{"unknown source", DiscoveredCode{Sources: "Elsewhere"}, 0.5},
+		{"channel", DiscoveredCode{Sources: "Announcements"}, 0.9},
+		{"sources add up", DiscoveredCode{Sources: "Fansite,Forum"}, 0.8},
+		{"trust is clamped", DiscoveredCode{Sources: "Broken"}, 1},
+		{"validated", DiscoveredCode{Sources: "Forum", Validation: CodeValidationValid}, 1},
+		{"rejected by the API", DiscoveredCode{Sources: "Official", Validation: CodeValidationInvalid}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.codeConfidence(tt.record); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("codeConfidence(%q) = %v, want %v", tt.record.Sources, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeScrapedCodes(t *testing.T) {
+	results := []ScrapeResult{
+		{SiteName: "A", Codes: []GiftCode{{Code: "ONE"}, {Code: "TWO", Description: "from A"}}},
+		{SiteName: "B", Codes: []GiftCode{{Code: "TWO", Description: "from B"}, {Code: "THREE"}}},
+	}
+	merged := mergeScrapedCodes(results)
+
+	var codes []string
+	for _, found := range merged {
+		codes = append(codes, found.Code)
+	}
+	if want := []string{"ONE", "TWO", "THREE"}; !reflect.DeepEqual(codes, want) {
+		t.Fatalf("codes = %q, want %q", codes, want)
+	}
+	if got, want := merged[1].sources, []string{"A", "B"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sources of TWO = %q, want %q", got, want)
+	}
+	if merged[1].Description != "from A" {
+		t.Errorf("description of TWO = %q, want the first one seen", merged[1].Description)
+	}
+	if got, want := joinSources([]string{"B", "A"}, []string{"A", "C"}), "A,B,C"; got != want {
+		t.Errorf("joinSources = %q, want %q", got, want)
+	}
+}
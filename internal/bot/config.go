@@ -12,17 +12,31 @@ import (
 	"github.com/spf13/viper"
 )
 
-var config *Config
-
+// LoadConfig reads config.yaml from the working directory, ./configs or
+// $HOME/.the-keeper.
 func LoadConfig() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./configs")
-	viper.AddConfigPath("$HOME/.the-keeper")
-	viper.AutomaticEnv()
+	v := viper.New()
+	v.SetConfigName("config")
+	v.AddConfigPath(".")
+	v.AddConfigPath("./configs")
+	v.AddConfigPath("$HOME/.the-keeper")
+	return loadConfig(v)
+}
+
+// LoadConfigFile reads the config from an explicit path.
+func LoadConfigFile(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	return loadConfig(v)
+}
+
+func loadConfig(v *viper.Viper) (*Config, error) {
+	v.SetConfigType("yaml")
+	v.AutomaticEnv()
+	v.SetDefault("suggestions.enabled", true)
+	v.SetDefault("glossary.creators_can_edit", true)
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			fmt.Println("No config file found. Using default values and environment variables.")
 		} else {
@@ -30,8 +44,8 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
-	config = &Config{}
-	if err := viper.Unmarshal(config); err != nil {
+	config := &Config{}
+	if err := v.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
@@ -78,6 +92,12 @@ func LoadConfig() (*Config, error) {
 	if notificationChannelID := os.Getenv("DISCORD_NOTIFICATION_CHANNEL_ID"); notificationChannelID != "" {
 		config.Discord.NotificationChannelID = notificationChannelID
 	}
+	if adminChannelID := os.Getenv("DISCORD_ADMIN_CHANNEL_ID"); adminChannelID != "" {
+		config.Discord.AdminChannelID = adminChannelID
+	}
+	if auditChannelID := os.Getenv("DISCORD_AUDIT_CHANNEL_ID"); auditChannelID != "" {
+		config.Discord.AuditChannelID = auditChannelID
+	}
 	if giftCodeSalt := os.Getenv("GIFT_CODE_SALT"); giftCodeSalt != "" {
 		config.GiftCode.Salt = giftCodeSalt
 		fmt.Printf("Gift Code Salt set from environment: %s\n", config.GiftCode.Salt)
@@ -98,6 +118,90 @@ func LoadConfig() (*Config, error) {
 	if config.Paths.CommandsConfig == "" {
 		config.Paths.CommandsConfig = "configs/commands.yaml"
 	}
+	if config.Scrape.Concurrency <= 0 {
+		config.Scrape.Concurrency = 4
+	}
+	if config.Scrape.Timeout == 0 {
+		config.Scrape.Timeout = 30 * time.Second
+	}
+	if config.Scrape.RetryDelay == 0 {
+		config.Scrape.RetryDelay = 2 * time.Second
+	}
+	if config.Scrape.UserAgent == "" {
+		config.Scrape.UserAgent = "the-keeper/1.0 (+https://github.com/greysquirr3l/the-keeper)"
+	}
+	if config.Scrape.Schedule.Interval <= 0 {
+		config.Scrape.Schedule.Interval = time.Hour
+	}
+	if config.Scrape.Fixtures.Dir == "" {
+		config.Scrape.Fixtures.Dir = "testdata/scrape"
+	}
+	if config.Scrape.Confidence.Threshold == 0 {
+		config.Scrape.Confidence.Threshold = 0.75
+	}
+	if config.Scrape.Confidence.DefaultTrust == 0 {
+		config.Scrape.Confidence.DefaultTrust = 0.8
+	}
+	if config.Scrape.Health.FailureThreshold <= 0 {
+		config.Scrape.Health.FailureThreshold = 3
+	}
+	if config.Scrape.Health.BaselineWindow <= 0 {
+		config.Scrape.Health.BaselineWindow = 10
+	}
+	if config.Scrape.Health.MinBaseline == 0 {
+		config.Scrape.Health.MinBaseline = 1
+	}
+	if config.Notifications.WebhookTimeout == 0 {
+		config.Notifications.WebhookTimeout = 10 * time.Second
+	}
+	if config.Notifications.WebhookRetries == 0 {
+		config.Notifications.WebhookRetries = 3
+	}
+	if config.Notifications.WebhookRetryDelay == 0 {
+		config.Notifications.WebhookRetryDelay = 5 * time.Second
+	}
+	if config.Digest.Daily.Time == "" {
+		config.Digest.Daily.Time = "09:00"
+	}
+	if config.Digest.Weekly.Time == "" {
+		config.Digest.Weekly.Time = "09:00"
+	}
+	if config.Digest.Weekly.Weekday == "" {
+		config.Digest.Weekly.Weekday = "mon"
+	}
+	if config.AutoReply.ChannelInterval == 0 {
+		config.AutoReply.ChannelInterval = 10 * time.Second
+	}
+	if config.AutoReply.TermCooldown == 0 {
+		config.AutoReply.TermCooldown = 5 * time.Minute
+	}
+	if config.AutoReply.MaxTerms <= 0 {
+		config.AutoReply.MaxTerms = 3
+	}
+	if config.GiftCode.MinLength == 0 {
+		config.GiftCode.MinLength = 6
+	}
+	if config.GiftCode.MaxLength == 0 {
+		config.GiftCode.MaxLength = 12
+	}
+	if config.GiftCode.MinLength < 0 || config.GiftCode.MinLength > config.GiftCode.MaxLength {
+		return nil, fmt.Errorf("invalid gift code lengths: min_length %d must be between 0 and max_length %d",
+			config.GiftCode.MinLength, config.GiftCode.MaxLength)
+	}
+	// Scrape state and health are stored by site name
+	siteNames := make(map[string]bool)
+	for _, site := range config.Scrape.Sites {
+		if siteNames[site.Name] {
+			return nil, fmt.Errorf("duplicate scrape site name '%s'", site.Name)
+		}
+		siteNames[site.Name] = true
+	}
+	if config.Suggestions.MaxDistance == 0 {
+		config.Suggestions.MaxDistance = 2
+	}
+	if config.Suggestions.MaxResults == 0 {
+		config.Suggestions.MaxResults = 3
+	}
 
 	logrus.WithFields(logrus.Fields{
 		"DiscordEnabled": config.Discord.Enabled,
@@ -113,10 +217,6 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
-func GetConfig() *Config {
-	return config
-}
-
 func InitializeLogger(config *Config) *logrus.Logger {
 	log := logrus.New()
 	level, err := logrus.ParseLevel(config.Logging.LogLevel)
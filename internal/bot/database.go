@@ -13,9 +13,6 @@ import (
 	gormlogger "gorm.io/gorm/logger"
 )
 
-var db *gorm.DB
-var dbLogger *logrus.Logger
-
 // CustomGormLogger adapts logrus to implement gorm's logger interface
 type CustomGormLogger struct {
 	Logger   *logrus.Logger
@@ -65,11 +62,8 @@ func (l CustomGormLogger) Trace(ctx context.Context, begin time.Time, fc func()
 }
 
 func InitDB(config *Config, logger *logrus.Logger) (*gorm.DB, error) {
-	dbLogger = logger
-	var err error
-
 	// Open the database connection
-	db, err = gorm.Open(sqlite.Open(config.Database.Path), &gorm.Config{
+	db, err := gorm.Open(sqlite.Open(config.Database.Path), &gorm.Config{
 		Logger: CustomGormLogger{
 			Logger:   logger,
 			logLevel: gormlogger.Info, // Set default log level here (Silent, Error, Warn, Info)
@@ -85,7 +79,7 @@ func InitDB(config *Config, logger *logrus.Logger) (*gorm.DB, error) {
 	}
 
 	// Run the migrations using gormigrate
-	if err := runMigrations(db); err != nil {
+	if err := runMigrations(db, logger); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
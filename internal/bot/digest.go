@@ -0,0 +1,314 @@
+// File: internal/bot/digest.go
+
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Digest periods
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// digestListLimit caps how many codes or players a digest lists by name.
+const digestListLimit = 10
+
+// DigestReport summarizes the bot's activity over a period.
+type DigestReport struct {
+	Period     string
+	From       time.Time
+	To         time.Time
+	NewCodes   []DiscoveredCode
+	Deploys    []CodeDeployStats
+	Registered []string // Discord IDs of players who added a player ID
+	Departed   []string // Discord IDs of players who removed theirs
+	Health     []ScrapeSiteHealth
+}
+
+// CodeDeployStats counts the redemptions of one gift code.
+type CodeDeployStats struct {
+	Code      string
+	Succeeded int
+	Total     int
+}
+
+// SuccessRate returns the share of successful redemptions from 0 to 1.
+func (s CodeDeployStats) SuccessRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Succeeded) / float64(s.Total)
+}
+
+// DigestDuration returns how far back a digest of the given period looks.
+func DigestDuration(period string) (time.Duration, error) {
+	switch period {
+	case DigestDaily:
+		return 24 * time.Hour, nil
+	case DigestWeekly:
+		return 7 * 24 * time.Hour, nil
+	default:
+		return 0, fmt.Errorf("unknown digest period '%s', expected %s or %s", period, DigestDaily, DigestWeekly)
+	}
+}
+
+// BuildDigest collects the activity between from and to.
+func (b *Bot) BuildDigest(period string, from, to time.Time) (DigestReport, error) {
+	report := DigestReport{Period: period, From: from, To: to}
+
+	err := b.DB.Where("first_seen_at >= ? AND first_seen_at < ?", from, to).
+		Order("first_seen_at").Find(&report.NewCodes).Error
+	if err != nil {
+		return report, fmt.Errorf("error loading new codes: %w", err)
+	}
+
+	err = b.DB.Model(&GiftCodeRedemption{}).
+		Select("gift_code AS code, SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS succeeded, COUNT(*) AS total", "Success").
+		Where("redeemed_at >= ? AND redeemed_at < ?", from, to).
+		Group("gift_code").Order("total DESC").
+		Scan(&report.Deploys).Error
+	if err != nil {
+		return report, fmt.Errorf("error loading redemptions: %w", err)
+	}
+
+	// Player registrations are recorded in the audit log only
+	var events []AuditEvent
+	err = b.DB.Where("action IN ? AND created_at >= ? AND created_at < ?", []string{"id.add", "id.remove"}, from, to).
+		Order("created_at").Find(&events).Error
+	if err != nil {
+		return report, fmt.Errorf("error loading player changes: %w", err)
+	}
+	for _, event := range events {
+		if event.Action == "id.add" {
+			report.Registered = append(report.Registered, event.Target)
+		} else {
+			report.Departed = append(report.Departed, event.Target)
+		}
+	}
+
+	report.Health = b.ListScrapeHealth()
+	return report, nil
+}
+
+// Embed renders the digest for Discord.
+func (r DigestReport) Embed(location *time.Location) *discordgo.MessageEmbed {
+	const layout = "Jan 2 15:04"
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("📊 %s%s digest", strings.ToUpper(r.Period[:1]), r.Period[1:]),
+		Description: fmt.Sprintf("%s to %s (%s)",
+			r.From.In(location).Format(layout), r.To.In(location).Format(layout), location),
+		Timestamp: r.To.Format(time.RFC3339),
+	}
+
+	codes := make([]string, 0, len(r.NewCodes))
+	for _, code := range r.NewCodes {
+		codes = append(codes, fmt.Sprintf("`%s` %s", code.Code, code.Status))
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  fmt.Sprintf("New codes (%d)", len(r.NewCodes)),
+		Value: digestList(codes),
+	})
+
+	succeeded, total := 0, 0
+	deploys := make([]string, 0, len(r.Deploys))
+	for _, stats := range r.Deploys {
+		succeeded += stats.Succeeded
+		total += stats.Total
+		deploys = append(deploys, fmt.Sprintf("`%s` %d/%d (%.0f%%)", stats.Code, stats.Succeeded, stats.Total, stats.SuccessRate()*100))
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  fmt.Sprintf("Redemptions (%d/%d succeeded)", succeeded, total),
+		Value: digestList(deploys),
+	})
+
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Registered players (%d)", len(r.Registered)),
+			Value:  digestList(userMentions(r.Registered)),
+			Inline: true,
+		},
+		&discordgo.MessageEmbedField{
+			Name:   fmt.Sprintf("Departed players (%d)", len(r.Departed)),
+			Value:  digestList(userMentions(r.Departed)),
+			Inline: true,
+		},
+	)
+
+	health := make([]string, 0, len(r.Health))
+	for _, site := range r.Health {
+		switch {
+		case site.Runs == 0:
+			health = append(health, fmt.Sprintf("⚠️ %s: not run yet", site.SiteName))
+		case site.Alerting || site.ConsecutiveFailures > 0:
+			health = append(health, fmt.Sprintf("𐄂 %s: %d failures in a row", site.SiteName, site.ConsecutiveFailures))
+		default:
+			health = append(health, fmt.Sprintf("✓ %s: %d codes last run", site.SiteName, site.LastCodeCount))
+		}
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+		Name:  "Scraper health",
+		Value: digestList(health),
+	})
+	return embed
+}
+
+func userMentions(userIDs []string) []string {
+	lines := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		lines[i] = fmt.Sprintf("<@%s>", userID)
+	}
+	return lines
+}
+
+// digestList joins lines for an embed field, listing at most digestListLimit.
+func digestList(lines []string) string {
+	if len(lines) == 0 {
+		return "_none_"
+	}
+	if len(lines) > digestListLimit {
+		more := len(lines) - digestListLimit
+		lines = append(lines[:digestListLimit:digestListLimit], fmt.Sprintf("… and %d more", more))
+	}
+	return Truncate(strings.Join(lines, "\n"), 1024)
+}
+
+// digestChannel returns the channel digests are posted to.
+func (b *Bot) digestChannel() string {
+	if b.Config.Digest.ChannelID != "" {
+		return b.Config.Digest.ChannelID
+	}
+	return b.Config.Discord.AdminChannelID
+}
+
+func (b *Bot) digestLocation() (*time.Location, error) {
+	if b.Config.Digest.Timezone == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(b.Config.Digest.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid digest timezone: %w", err)
+	}
+	return location, nil
+}
+
+// SendDigest builds the digest of the period ending now and posts it to
+// channelID.
+func (b *Bot) SendDigest(channelID, period string) error {
+	if b.Session == nil {
+		return fmt.Errorf("discord is disabled")
+	}
+	if channelID == "" {
+		return fmt.Errorf("no digest channel is configured")
+	}
+	duration, err := DigestDuration(period)
+	if err != nil {
+		return err
+	}
+	location, err := b.digestLocation()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	report, err := b.BuildDigest(period, now.Add(-duration), now)
+	if err != nil {
+		return err
+	}
+	if _, err := b.Session.ChannelMessageSendEmbed(channelID, report.Embed(location)); err != nil {
+		return fmt.Errorf("error posting digest: %w", err)
+	}
+	return nil
+}
+
+// digestSchedule is the time of day, and for weekly digests the weekday, at
+// which a digest is posted.
+type digestSchedule struct {
+	period  string
+	hour    int
+	minute  int
+	weekday time.Weekday
+}
+
+func newDigestSchedule(period string, cfg DigestSchedule) (digestSchedule, error) {
+	schedule := digestSchedule{period: period}
+	at, err := time.Parse("15:04", cfg.Time)
+	if err != nil {
+		return schedule, fmt.Errorf("invalid %s digest time '%s', expected HH:MM", period, cfg.Time)
+	}
+	schedule.hour, schedule.minute = at.Hour(), at.Minute()
+	if period == DigestWeekly {
+		weekday, ok := weekdayNames[strings.ToLower(cfg.Weekday)]
+		if !ok {
+			return schedule, fmt.Errorf("invalid weekly digest weekday '%s'", cfg.Weekday)
+		}
+		schedule.weekday = weekday
+	}
+	return schedule, nil
+}
+
+// next returns the first time after t at which the digest is due.
+func (s digestSchedule) next(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), s.hour, s.minute, 0, 0, location)
+	if s.period == DigestWeekly {
+		next = next.AddDate(0, 0, (int(s.weekday)-int(next.Weekday())+7)%7)
+	}
+	for !next.After(t) {
+		if s.period == DigestWeekly {
+			next = next.AddDate(0, 0, 7)
+		} else {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+// StartDigests posts the enabled daily and weekly digests on schedule until
+// the bot shuts down.
+func (b *Bot) StartDigests() {
+	location, err := b.digestLocation()
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Digests disabled")
+		return
+	}
+
+	var schedules []digestSchedule
+	for period, cfg := range map[string]DigestSchedule{DigestDaily: b.Config.Digest.Daily, DigestWeekly: b.Config.Digest.Weekly} {
+		if !cfg.Enabled {
+			continue
+		}
+		schedule, err := newDigestSchedule(period, cfg)
+		if err != nil {
+			b.GetLogger().WithError(err).Error("Digest disabled")
+			continue
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	for _, schedule := range schedules {
+		go func() {
+			for {
+				due := schedule.next(time.Now(), location)
+				timer := time.NewTimer(time.Until(due))
+				select {
+				case <-timer.C:
+				case <-b.ctx.Done():
+					timer.Stop()
+					return
+				}
+
+				if err := b.SendDigest(b.digestChannel(), schedule.period); err != nil {
+					b.GetLogger().WithError(err).WithField("period", schedule.period).Error("Error sending digest")
+				} else {
+					b.GetLogger().WithField("period", schedule.period).Info("Digest sent")
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,71 @@
+// File: internal/bot/digest_test.go
+
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDigestSchedule(t *testing.T) {
+	tests := []struct {
+		name    string
+		period  string
+		cfg     DigestSchedule
+		wantErr bool
+	}{
+		{"daily", DigestDaily, DigestSchedule{Time: "09:30"}, false},
+		{"weekly", DigestWeekly, DigestSchedule{Time: "18:00", Weekday: "Fri"}, false},
+		{"daily ignores weekday", DigestDaily, DigestSchedule{Time: "09:30", Weekday: "someday"}, false},
+		{"bad time", DigestDaily, DigestSchedule{Time: "9am"}, true},
+		{"out of range", DigestDaily, DigestSchedule{Time: "24:00"}, true},
+		{"bad weekday", DigestWeekly, DigestSchedule{Time: "18:00", Weekday: "someday"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newDigestSchedule(tt.period, tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newDigestSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDigestScheduleNext(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	daily := digestSchedule{period: DigestDaily, hour: 9, minute: 30}
+	weekly := digestSchedule{period: DigestWeekly, hour: 18, weekday: time.Monday}
+	// 2024-01-01 is a Monday
+	utc := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule digestSchedule
+		t        time.Time
+		location *time.Location
+		want     time.Time
+	}{
+		{"later today", daily, utc(1, 8, 0), time.UTC, utc(1, 9, 30)},
+		{"tomorrow", daily, utc(1, 10, 0), time.UTC, utc(2, 9, 30)},
+		{"exactly due", daily, utc(1, 9, 30), time.UTC, utc(2, 9, 30)},
+		{"local time", daily, utc(1, 8, 0), berlin, utc(1, 8, 30)},
+		{"local date", daily, utc(1, 23, 30), berlin, utc(2, 8, 30)},
+		{"this week", weekly, utc(1, 12, 0), time.UTC, utc(1, 18, 0)},
+		{"next week", weekly, utc(1, 18, 0), time.UTC, utc(8, 18, 0)},
+		{"later in the week", weekly, utc(3, 12, 0), time.UTC, utc(8, 18, 0)},
+		{"daylight saving", daily, time.Date(2024, 3, 30, 12, 0, 0, 0, time.UTC), berlin,
+			time.Date(2024, 3, 31, 7, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.next(tt.t, tt.location); !got.Equal(tt.want) {
+				t.Errorf("next(%v) = %v, want %v", tt.t, got.UTC(), tt.want)
+			}
+		})
+	}
+}
@@ -9,127 +9,47 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-var (
-	discordSession *discordgo.Session
-	discordLogger  *logrus.Logger
-)
-
-func InitDiscord(token string, logger *logrus.Logger) error {
-	discordLogger = logger
-	discordLogger.Info("Initializing Discord bot...")
-
-	// Enable discordgo debug logging
-	discordgo.Logger = func(msgL, caller int, format string, a ...interface{}) {
-		discordLogger.Debugf(format, a...)
-	}
+// openDiscord wires the event handlers onto the bot's session and opens it.
+func (b *Bot) openDiscord() error {
+	logger := b.GetLogger()
+	logger.Info("Initializing Discord bot...")
 
-	var err error
-	discordLogger.Info("Creating new Discord session...")
-	discordSession, err = discordgo.New("Bot " + token)
-	if err != nil {
-		return fmt.Errorf("error creating Discord session: %w", err)
+	// discordgo can only be given a logger through a package variable, which
+	// would be shared by every bot, so it keeps its own and only logs more in
+	// debug mode
+	if logger.IsLevelEnabled(logrus.DebugLevel) {
+		b.Session.LogLevel = discordgo.LogDebug
 	}
 
-	// Set the log level for discordgo to debug
-	discordSession.LogLevel = discordgo.LogDebug
-
-	discordLogger.Info("Setting up intents...")
-	discordSession.Identify.Intents = discordgo.IntentsGuilds |
+	logger.Info("Setting up intents...")
+	b.Session.Identify.Intents = discordgo.IntentsGuilds |
 		discordgo.IntentsGuildMessages |
 		discordgo.IntentsMessageContent
 
-	discordLogger.Info("Adding message handler...")
-	discordSession.AddHandler(messageCreate)
+	logger.Info("Adding message handler...")
+	b.Session.AddHandler(b.messageCreate)
+	b.Session.AddHandler(b.interactionCreate)
 
 	// Add connect and disconnect handlers
-	discordSession.AddHandler(func(s *discordgo.Session, _ *discordgo.Connect) {
-		discordLogger.Info("Bot has connected to Discord")
+	b.Session.AddHandler(func(s *discordgo.Session, _ *discordgo.Connect) {
+		logger.Info("Bot has connected to Discord")
 	})
-	discordSession.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
-		discordLogger.Warn("Bot has disconnected from Discord")
+	b.Session.AddHandler(func(s *discordgo.Session, _ *discordgo.Disconnect) {
+		logger.Warn("Bot has disconnected from Discord")
 	})
 
-	discordLogger.Info("Opening Discord connection...")
-	err = discordSession.Open()
-	if err != nil {
+	logger.Info("Opening Discord connection...")
+	if err := b.Session.Open(); err != nil {
 		return fmt.Errorf("error opening connection: %w", err)
 	}
 
 	// Set a custom status
-	err = discordSession.UpdateGameStatus(0, "Ready to serve!")
-	if err != nil {
-		discordLogger.Errorf("Error setting presence: %v", err)
-	}
-
-	discordLogger.Infof("Discord bot is now running with username: %s and ID: %s",
-		discordSession.State.User.Username,
-		discordSession.State.User.ID)
-	return nil
-}
-
-func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
-	if m.Author.ID == s.State.User.ID {
-		return
-	}
-
-	discordLogger.Debugf("Received message: %s from user: %s", m.Content, m.Author.Username)
-
-	bot := GetBot()
-	config := bot.Config
-	err := LoadCommands(config.Paths.CommandsConfig, discordLogger, bot.HandlerRegistry)
-	if err != nil {
-		discordLogger.Errorf("Failed to load command config: %v", err)
-		return
-	}
-
-	HandleCommand(s, m, config)
-}
-
-// SendMessage is a helper function to send a message to a channel
-func SendMessage(s *discordgo.Session, channelID string, message string) error {
-	_, err := s.ChannelMessageSend(channelID, message)
-	if err != nil {
-		discordLogger.Errorf("Error sending message: %v", err)
-	}
-	return err
-}
-
-// Helper function to check if a user has a specific role
-func HasRole(s *discordgo.Session, guildID string, userID string, roleID string) bool {
-	member, err := s.GuildMember(guildID, userID)
-	if err != nil {
-		discordLogger.Errorf("Error fetching guild member: %v", err)
-		return false
-	}
-
-	for _, r := range member.Roles {
-		if r == roleID {
-			return true
-		}
+	if err := b.Session.UpdateGameStatus(0, "Ready to serve!"); err != nil {
+		logger.Errorf("Error setting presence: %v", err)
 	}
-	return false
-}
 
-func IsAdmin(s *discordgo.Session, guildID, userID string) bool {
-	member, err := s.GuildMember(guildID, userID)
-	if err != nil {
-		discordLogger.Errorf("Error fetching guild member: %v", err)
-		return false
-	}
-
-	for _, roleID := range member.Roles {
-		if roleID == GetBot().Config.Discord.RoleID {
-			return true
-		}
-	}
-
-	return false
-}
-
-func CloseDiscord() error {
-	if discordSession != nil {
-		discordLogger.Info("Closing Discord session...")
-		return discordSession.Close()
-	}
+	logger.Infof("Discord bot is now running with username: %s and ID: %s",
+		b.Session.State.User.Username,
+		b.Session.State.User.ID)
 	return nil
 }
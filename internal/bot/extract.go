@@ -0,0 +1,73 @@
+// File: internal/bot/extract.go
+
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// codePattern returns the pattern a site's candidates must match. Sites
+// without a code_pattern fall back to the configured gift code length limits.
+func (b *Bot) codePattern(site ScrapeSite) (*regexp.Regexp, error) {
+	if site.CodePattern != "" {
+		pattern, err := regexp.Compile(site.CodePattern)
+		if err != nil {
+			return nil, fmt.Errorf("site %s: invalid code_pattern: %w", site.Name, err)
+		}
+		return pattern, nil
+	}
+	pattern, err := regexp.Compile(fmt.Sprintf(`^[A-Za-z0-9]{%d,%d}$`, b.Config.GiftCode.MinLength, b.Config.GiftCode.MaxLength))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gift code min_length/max_length: %w", err)
+	}
+	return pattern, nil
+}
+
+// NormalizeCode applies the site's whitespace and case rules to a scraped code.
+func NormalizeCode(site ScrapeSite, code string) string {
+	code = strings.TrimSpace(code)
+	if site.StripWhitespace {
+		code = strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+			return r
+		}, code)
+	}
+	switch strings.ToLower(site.CodeCase) {
+	case "upper":
+		code = strings.ToUpper(code)
+	case "lower":
+		code = strings.ToLower(code)
+	}
+	return code
+}
+
+// filterCodes normalizes the extracted candidates, drops duplicates and
+// returns how many candidates were rejected by the code pattern.
+func (b *Bot) filterCodes(site ScrapeSite, candidates []GiftCode) ([]GiftCode, int, error) {
+	pattern, err := b.codePattern(site)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := make(map[string]bool)
+	var codes []GiftCode
+	rejected := 0
+	for _, candidate := range candidates {
+		candidate.Code = NormalizeCode(site, candidate.Code)
+		if !pattern.MatchString(candidate.Code) {
+			rejected++
+			continue
+		}
+		if seen[candidate.Code] {
+			continue
+		}
+		seen[candidate.Code] = true
+		codes = append(codes, candidate)
+	}
+	return codes, rejected, nil
+}
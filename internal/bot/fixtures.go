@@ -0,0 +1,120 @@
+// File: internal/bot/fixtures.go
+
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var fixtureSlugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FixtureName returns the base file name of a site's recorded response.
+// The matching expected codes are stored next to it with a .codes.json suffix.
+func FixtureName(site ScrapeSite) string {
+	slug := strings.Trim(fixtureSlugRegex.ReplaceAllString(strings.ToLower(site.Name), "-"), "-")
+	switch strings.ToLower(site.Type) {
+	case SourceTypeJSON:
+		return slug + ".json"
+	case SourceTypeRSS, SourceTypeAtom, "feed":
+		return slug + ".xml"
+	case SourceTypeRegex:
+		return slug + ".txt"
+	default:
+		return slug + ".html"
+	}
+}
+
+func (b *Bot) fixturePath(site ScrapeSite) string {
+	return filepath.Join(b.Config.Scrape.Fixtures.Dir, FixtureName(site))
+}
+
+func expectedCodesPath(fixturePath string) string {
+	return strings.TrimSuffix(fixturePath, filepath.Ext(fixturePath)) + ".codes.json"
+}
+
+// readFixture serves a site's recorded response in replay mode.
+func (b *Bot) readFixture(site ScrapeSite) (fetchResult, error) {
+	body, err := os.ReadFile(b.fixturePath(site))
+	if err != nil {
+		return fetchResult{}, fmt.Errorf("error reading fixture: %w", err)
+	}
+	return fetchResult{Body: body}, nil
+}
+
+// saveFixture stores a site's raw response together with the codes
+// extracted from it, which the fixture tests expect to find again.
+func (b *Bot) saveFixture(site ScrapeSite, body []byte, codes []GiftCode) error {
+	path := b.fixturePath(site)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("error creating fixtures directory: %w", err)
+	}
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		return fmt.Errorf("error writing fixture: %w", err)
+	}
+
+	expected := make([]string, len(codes))
+	for i, code := range codes {
+		expected[i] = code.Code
+	}
+	data, err := json.MarshalIndent(expected, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding expected codes: %w", err)
+	}
+	if err := os.WriteFile(expectedCodesPath(path), append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("error writing expected codes: %w", err)
+	}
+	return nil
+}
+
+// LoadExpectedCodes reads the codes recorded alongside a site's fixture.
+func (b *Bot) LoadExpectedCodes(site ScrapeSite) ([]string, error) {
+	data, err := os.ReadFile(expectedCodesPath(b.fixturePath(site)))
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	if err := json.Unmarshal(data, &codes); err != nil {
+		return nil, fmt.Errorf("error parsing expected codes: %w", err)
+	}
+	return codes, nil
+}
+
+// ExtractFixture runs a site's extraction rules over its recorded response.
+func (b *Bot) ExtractFixture(site ScrapeSite) ([]GiftCode, error) {
+	fetched, err := b.readFixture(site)
+	if err != nil {
+		return nil, err
+	}
+	codes, _, err := b.extractCodes(site, fetched.Body)
+	return codes, err
+}
+
+// RecordFixtures fetches each site afresh and saves its response as a
+// fixture. Recording doesn't touch the scrape state or announce any codes.
+func (b *Bot) RecordFixtures(ctx context.Context, sites []ScrapeSite, actor Actor) []ScrapeResult {
+	b.audit(actor, "scrape.record", b.scrapeTarget(sites), "", "")
+	results := make([]ScrapeResult, len(sites))
+	for i, site := range sites {
+		result := ScrapeResult{SiteName: site.Name, Status: ScrapeStatusError}
+		fetched, err := b.fetchNetwork(ctx, site, ScrapeSiteState{})
+		if err == nil {
+			result.Codes, result.Rejected, err = b.extractCodes(site, fetched.Body)
+		}
+		if err == nil {
+			err = b.saveFixture(site, fetched.Body, result.Codes)
+		}
+		if err != nil {
+			result.Error = err
+		} else {
+			result.Status = ScrapeStatusChanged
+		}
+		results[i] = result
+	}
+	return results
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/md5"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,15 +16,38 @@ import (
 	"time"
 )
 
-var baseURL string
-
-// SetGiftCodeBaseURL sets the base URL for gift code API requests.
-func SetGiftCodeBaseURL(config *Config) {
-	baseURL = config.GiftCode.APIEndpoint
-}
+// Error codes returned by the gift code API
+const (
+	giftCodeOK       = 20000
+	giftCodeExpired  = 40007
+	giftCodeClaimed  = 40008
+	giftCodeNotFound = 40014
+)
 
 // ValidateGiftCode checks if a gift code is valid for a player.
 func (b *Bot) ValidateGiftCode(giftCode, playerID string) (bool, string) {
+	errCode, msg, err := b.checkGiftCode(context.Background(), giftCode, playerID)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	switch errCode {
+	case giftCodeOK:
+		return true, "Gift code is valid"
+	case giftCodeNotFound:
+		return false, "Gift Code not found"
+	case giftCodeExpired:
+		b.notifyCodeExpired(giftCode)
+		return false, "Expired, unable to claim"
+	case giftCodeClaimed:
+		return false, "Gift code already claimed"
+	default:
+		return false, fmt.Sprintf("Unknown error: %v", msg)
+	}
+}
+
+// checkGiftCode submits a gift code for a player and returns the API error code and message.
+func (b *Bot) checkGiftCode(ctx context.Context, giftCode, playerID string) (int, interface{}, error) {
 	data := map[string]string{
 		"fid":  playerID,
 		"cdk":  giftCode,
@@ -32,28 +56,16 @@ func (b *Bot) ValidateGiftCode(giftCode, playerID string) (bool, string) {
 
 	signedData := b.appendSign(data)
 
-	resp, err := b.makeAPIRequest(context.Background(), "/gift_code", signedData)
+	resp, err := b.makeAPIRequest(ctx, "/gift_code", signedData)
 	if err != nil {
-		return false, fmt.Sprintf("API request failed: %v", err)
+		return 0, nil, fmt.Errorf("API request failed: %v", err)
 	}
 
 	errCode, ok := resp["err_code"].(float64)
 	if !ok {
-		return false, "Invalid error code format"
-	}
-
-	switch int(errCode) {
-	case 20000:
-		return true, "Gift code is valid"
-	case 40014:
-		return false, "Gift Code not found"
-	case 40007:
-		return false, "Expired, unable to claim"
-	case 40008:
-		return false, "Gift code already claimed"
-	default:
-		return false, fmt.Sprintf("Unknown error: %v", resp["msg"])
+		return 0, nil, fmt.Errorf("Invalid error code format")
 	}
+	return int(errCode), resp["msg"], nil
 }
 
 func (b *Bot) appendSign(data map[string]string) map[string]string {
@@ -134,13 +146,14 @@ func (b *Bot) RedeemGiftCode(playerID, giftCode string) (bool, string, error) {
 	}
 
 	switch int(errCode) {
-	case 20000:
+	case giftCodeOK:
 		return true, "Gift code redeemed successfully", nil
-	case 40014:
+	case giftCodeNotFound:
 		return false, "Gift Code not found", nil
-	case 40007:
+	case giftCodeExpired:
+		b.notifyCodeExpired(giftCode)
 		return false, "Expired, unable to claim", nil
-	case 40008:
+	case giftCodeClaimed:
 		return false, "Gift code already claimed", nil
 	default:
 		return false, fmt.Sprintf("Unknown error: %v", resp["msg"]), nil
@@ -157,7 +170,7 @@ func (b *Bot) makeAPIRequest(ctx context.Context, endpoint string, data map[stri
 		form.Add(k, v)
 	}
 
-	fullURL := baseURL + endpoint
+	fullURL := b.Config.GiftCode.APIEndpoint + endpoint
 	b.logger.Infof("Making request to API endpoint: %s", fullURL)
 	b.logger.Debugf("Request data: %v", data)
 
@@ -184,6 +197,85 @@ func (b *Bot) makeAPIRequest(ctx context.Context, endpoint string, data map[stri
 	return result, nil
 }
 
+// ErrNoPlayerID is returned when a Discord user hasn't linked a player ID.
+var ErrNoPlayerID = errors.New("no player ID associated")
+
+// RedeemForUser redeems a gift code for the player linked to a Discord user
+// and records the attempt. The message is the API's answer for the player.
+func (b *Bot) RedeemForUser(discordID, giftCode string) (bool, string, error) {
+	playerID, err := b.GetPlayerID(discordID)
+	if err != nil {
+		return false, "", ErrNoPlayerID
+	}
+
+	success, message, err := b.RedeemGiftCode(playerID, giftCode)
+	if err != nil {
+		return false, "", fmt.Errorf("error redeeming gift code: %w", err)
+	}
+
+	status := "Success"
+	if !success {
+		status = "Failed"
+	}
+	if err := b.RecordGiftCodeRedemption(discordID, playerID, giftCode, status); err != nil {
+		return success, message, fmt.Errorf("gift code redeemed but failed to record: %w", err)
+	}
+	return success, message, nil
+}
+
+// DeployResult is the outcome of redeeming a deployed gift code for one player.
+type DeployResult struct {
+	DiscordID string
+	PlayerID  string
+	Success   bool
+	Message   string
+	Err       error
+}
+
+// DeployGiftCode redeems a gift code for every registered player and records
+// each redemption. report, if not nil, is called after every player, and
+// players who opted into DMs are told the outcome.
+func (b *Bot) DeployGiftCode(giftCode string, actor Actor, report func(DeployResult)) (succeeded, total int, err error) {
+	playerIDs, err := b.GetAllPlayerIDs()
+	if err != nil {
+		return 0, 0, fmt.Errorf("error retrieving Player IDs: %w", err)
+	}
+
+	for discordID, playerID := range playerIDs {
+		result := DeployResult{DiscordID: discordID, PlayerID: playerID}
+		result.Success, result.Message, result.Err = b.RedeemGiftCode(playerID, giftCode)
+		if result.Err != nil {
+			b.logger.WithError(result.Err).
+				WithField("player_id", playerID).
+				WithField("gift_code", giftCode).
+				Error("Error redeeming gift code")
+		} else {
+			status := "Success"
+			if result.Success {
+				succeeded++
+			} else {
+				status = "Failed"
+			}
+			if err := b.RecordGiftCodeRedemption(discordID, playerID, giftCode, status); err != nil {
+				b.logger.WithError(err).Error("Gift code redeemed but failed to record in database")
+				result.Err = fmt.Errorf("gift code redeemed but failed to record: %w", err)
+			}
+		}
+		if report != nil {
+			report(result)
+		}
+		b.dmRedemptionResult(giftCode, result)
+	}
+	b.Notify(Notification{
+		Event: EventDeployFinished,
+		Data:  DeployReport{Code: giftCode, Succeeded: succeeded, Total: len(playerIDs)},
+	})
+	if len(playerIDs) > 0 {
+		b.audit(actor, "giftcode.deploy", giftCode, "", fmt.Sprintf("%d of %d players succeeded", succeeded, len(playerIDs)))
+	}
+	return succeeded, len(playerIDs), nil
+}
+
 // RecordGiftCodeRedemption records a gift code redemption in the database.
 func (b *Bot) RecordGiftCodeRedemption(discordID, playerID, giftCode, status string) error {
 	redemption := GiftCodeRedemption{
@@ -197,6 +289,20 @@ func (b *Bot) RecordGiftCodeRedemption(discordID, playerID, giftCode, status str
 	return result.Error
 }
 
+// GetAllGiftCodeRedemptions gets every gift code redemption, newest first.
+func (b *Bot) GetAllGiftCodeRedemptions() ([]GiftCodeRedemption, error) {
+	var redemptions []GiftCodeRedemption
+	result := b.DB.Order("redeemed_at desc").Find(&redemptions)
+	return redemptions, result.Error
+}
+
+// GetUserGiftCodeRedemptions gets every redemption for a specific user, newest first.
+func (b *Bot) GetUserGiftCodeRedemptions(discordID string) ([]GiftCodeRedemption, error) {
+	var redemptions []GiftCodeRedemption
+	result := b.DB.Where("discord_id = ?", discordID).Order("redeemed_at desc").Find(&redemptions)
+	return redemptions, result.Error
+}
+
 // GetAllGiftCodeRedemptionsPaginated gets all gift code redemptions for admins.
 func (b *Bot) GetAllGiftCodeRedemptionsPaginated(page, itemsPerPage int) ([]GiftCodeRedemption, error) {
 	var redemptions []GiftCodeRedemption
@@ -0,0 +1,354 @@
+// File: internal/bot/glossary.go
+
+package bot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// TermNamePattern matches term titles, aliases, categories and trigger
+// keywords after their spaces are normalized. Quotes are reserved for
+// wrapping multi-word titles in commands.
+var TermNamePattern = regexp.MustCompile(`^[^"“”\n]{1,64}$`)
+
+// Glossary file formats
+const (
+	GlossaryYAML     = "yaml"
+	GlossaryCSV      = "csv"
+	GlossaryMarkdown = "md"
+)
+
+// GlossaryFormats lists the formats the glossary can be exported to and
+// imported from.
+var GlossaryFormats = []string{GlossaryYAML, GlossaryCSV, GlossaryMarkdown}
+
+// glossaryListSeparator separates aliases and triggers in CSV cells. A
+// separator or backslash inside an alias or trigger is escaped with a
+// backslash.
+const glossaryListSeparator = '|'
+
+// csvHeader is the header row of CSV glossaries.
+var csvHeader = []string{"term", "description", "category", "aliases", "triggers"}
+
+// GlossaryEntry is a term as it is exported and imported.
+type GlossaryEntry struct {
+	Term        string   `yaml:"term"`
+	Description string   `yaml:"description"`
+	Category    string   `yaml:"category,omitempty"`
+	Aliases     []string `yaml:"aliases,omitempty"`
+	Triggers    []string `yaml:"triggers,omitempty"`
+}
+
+// ParseGlossaryFormat returns the format with the given name, accepting
+// "yml" and "markdown" as well.
+func ParseGlossaryFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "yaml", "yml":
+		return GlossaryYAML, nil
+	case "csv":
+		return GlossaryCSV, nil
+	case "md", "markdown":
+		return GlossaryMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown glossary format '%s', expected one of %s", name, strings.Join(GlossaryFormats, ", "))
+	}
+}
+
+// GlossaryFormatOf returns the format of a glossary file from its extension.
+func GlossaryFormatOf(filename string) (string, error) {
+	return ParseGlossaryFormat(filepath.Ext(filename))
+}
+
+// GlossaryEntries returns every term with its aliases and triggers, ordered
+// like ListTerms.
+func (b *Bot) GlossaryEntries() ([]GlossaryEntry, error) {
+	terms, err := b.ListTerms("")
+	if err != nil {
+		return nil, err
+	}
+	var triggers []TermTrigger
+	if err := b.DB.Order("keyword").Find(&triggers).Error; err != nil {
+		return nil, fmt.Errorf("error loading term triggers: %w", err)
+	}
+	keywords := make(map[uint][]string)
+	for _, trigger := range triggers {
+		keywords[trigger.TermID] = append(keywords[trigger.TermID], trigger.Keyword)
+	}
+
+	entries := make([]GlossaryEntry, len(terms))
+	for i, t := range terms {
+		entries[i] = GlossaryEntry{
+			Term:        t.Term,
+			Description: t.Description,
+			Category:    t.Category,
+			Aliases:     t.AliasList(),
+			Triggers:    keywords[t.ID],
+		}
+	}
+	return entries, nil
+}
+
+// EncodeGlossary renders glossary entries in the given format.
+func EncodeGlossary(entries []GlossaryEntry, format string) ([]byte, error) {
+	switch format {
+	case GlossaryYAML:
+		return yaml.Marshal(entries)
+	case GlossaryCSV:
+		return encodeGlossaryCSV(entries)
+	case GlossaryMarkdown:
+		return encodeGlossaryMarkdown(entries), nil
+	default:
+		return nil, fmt.Errorf("unknown glossary format '%s'", format)
+	}
+}
+
+// DecodeGlossary parses glossary entries in the given format.
+func DecodeGlossary(data []byte, format string) ([]GlossaryEntry, error) {
+	switch format {
+	case GlossaryYAML:
+		var entries []GlossaryEntry
+		if err := yaml.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("invalid YAML glossary: %w", err)
+		}
+		return entries, nil
+	case GlossaryCSV:
+		return decodeGlossaryCSV(data)
+	case GlossaryMarkdown:
+		return decodeGlossaryMarkdown(data)
+	default:
+		return nil, fmt.Errorf("unknown glossary format '%s'", format)
+	}
+}
+
+func encodeGlossaryCSV(entries []GlossaryEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		err := w.Write([]string{
+			entry.Term,
+			entry.Description,
+			entry.Category,
+			joinGlossaryList(entry.Aliases, glossaryListSeparator, "|"),
+			joinGlossaryList(entry.Triggers, glossaryListSeparator, "|"),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// decodeGlossaryCSV reads a CSV glossary. Columns are matched by the header
+// row, so they may come in any order and all but term and description are
+// optional.
+func decodeGlossaryCSV(data []byte) ([]GlossaryEntry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV glossary: %w", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range csvHeader[:2] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid CSV glossary: missing '%s' column", required)
+		}
+	}
+
+	var entries []GlossaryEntry
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV glossary: %w", err)
+		}
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		entries = append(entries, GlossaryEntry{
+			Term:        cell("term"),
+			Description: cell("description"),
+			Category:    cell("category"),
+			Aliases:     splitGlossaryList(cell("aliases"), glossaryListSeparator),
+			Triggers:    splitGlossaryList(cell("triggers"), glossaryListSeparator),
+		})
+	}
+}
+
+// joinGlossaryList joins aliases or triggers with joiner, escaping sep and
+// backslashes inside them.
+func joinGlossaryList(items []string, sep byte, joiner string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		item = strings.ReplaceAll(item, `\`, `\\`)
+		escaped[i] = strings.ReplaceAll(item, string(sep), `\`+string(sep))
+	}
+	return strings.Join(escaped, joiner)
+}
+
+// splitGlossaryList splits a list written by joinGlossaryList on the
+// unescaped separators, dropping empty items.
+func splitGlossaryList(list string, sep byte) []string {
+	var items []string
+	var item strings.Builder
+	add := func() {
+		if s := strings.TrimSpace(item.String()); s != "" {
+			items = append(items, s)
+		}
+		item.Reset()
+	}
+	for i := 0; i < len(list); i++ {
+		switch {
+		case list[i] == '\\' && i+1 < len(list):
+			i++
+			item.WriteByte(list[i])
+		case list[i] == sep:
+			add()
+		default:
+			item.WriteByte(list[i])
+		}
+	}
+	add()
+	return items
+}
+
+// Markdown glossaries have a "## Category" heading per category and a
+// "### Term" heading per term, followed by optional alias and trigger lines
+// and the description. Aliases and triggers are separated by commas, and
+// description lines that would be read as a heading or an alias or trigger
+// line are escaped with a leading backslash.
+const (
+	markdownAliasesPrefix  = "_Also known as: "
+	markdownTriggersPrefix = "_Triggers: "
+	markdownListSeparator  = ','
+)
+
+// markdownReserved reports whether a description line, ignoring leading
+// backslashes, would be mistaken for Markdown glossary structure.
+func markdownReserved(line string) bool {
+	line = strings.TrimLeft(line, `\`)
+	for _, prefix := range []string{"## ", "### ", markdownAliasesPrefix, markdownTriggersPrefix} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeMarkdownDescription(description string) string {
+	lines := strings.Split(strings.TrimSpace(description), "\n")
+	for i, line := range lines {
+		if markdownReserved(line) {
+			lines[i] = `\` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func unescapeMarkdownLine(line string) string {
+	if strings.HasPrefix(line, `\`) && markdownReserved(line) {
+		return line[1:]
+	}
+	return line
+}
+
+func encodeGlossaryMarkdown(entries []GlossaryEntry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("# Glossary\n")
+	category := ""
+	for i, entry := range entries {
+		entryCategory := entry.Category
+		if entryCategory == "" {
+			entryCategory = UncategorizedTerms
+		}
+		if i == 0 || entryCategory != category {
+			category = entryCategory
+			fmt.Fprintf(&buf, "\n## %s\n", category)
+		}
+		fmt.Fprintf(&buf, "\n### %s\n\n", entry.Term)
+		if len(entry.Aliases) > 0 {
+			fmt.Fprintf(&buf, "%s%s_\n", markdownAliasesPrefix, joinGlossaryList(entry.Aliases, markdownListSeparator, ", "))
+		}
+		if len(entry.Triggers) > 0 {
+			fmt.Fprintf(&buf, "%s%s_\n", markdownTriggersPrefix, joinGlossaryList(entry.Triggers, markdownListSeparator, ", "))
+		}
+		if len(entry.Aliases) > 0 || len(entry.Triggers) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(escapeMarkdownDescription(entry.Description))
+		buf.WriteString("\n")
+	}
+	return buf.Bytes()
+}
+
+// decodeGlossaryMarkdown reads a Markdown glossary.
+func decodeGlossaryMarkdown(data []byte) ([]GlossaryEntry, error) {
+	var entries []GlossaryEntry
+	var entry *GlossaryEntry
+	var description []string
+	category := ""
+	flush := func() {
+		if entry != nil {
+			entry.Description = strings.TrimSpace(strings.Join(description, "\n"))
+			entries = append(entries, *entry)
+		}
+		entry, description = nil, nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		switch {
+		case strings.HasPrefix(line, "### "):
+			flush()
+			entry = &GlossaryEntry{Term: strings.TrimSpace(line[4:]), Category: category}
+		case strings.HasPrefix(line, "## "):
+			flush()
+			category = strings.TrimSpace(line[3:])
+			if strings.EqualFold(category, UncategorizedTerms) {
+				category = ""
+			}
+		case entry == nil:
+			// Text before the first term, such as the title, is ignored
+		case len(description) == 0 && strings.HasPrefix(line, markdownAliasesPrefix) && strings.HasSuffix(line, "_"):
+			entry.Aliases = splitMarkdownList(line, markdownAliasesPrefix)
+		case len(description) == 0 && strings.HasPrefix(line, markdownTriggersPrefix) && strings.HasSuffix(line, "_"):
+			entry.Triggers = splitMarkdownList(line, markdownTriggersPrefix)
+		case len(description) == 0 && line == "":
+			// Skip the blank lines before the description
+		default:
+			description = append(description, unescapeMarkdownLine(line))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid Markdown glossary: %w", err)
+	}
+	flush()
+	return entries, nil
+}
+
+func splitMarkdownList(line, prefix string) []string {
+	return splitGlossaryList(strings.TrimSuffix(strings.TrimPrefix(line, prefix), "_"), markdownListSeparator)
+}
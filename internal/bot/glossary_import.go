@@ -0,0 +1,408 @@
+// File: internal/bot/glossary_import.go
+
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Glossary import conflict policies for entries whose title is taken. They
+// are also the outcome of each entry, along with ImportAdd for entries
+// without conflicts.
+const (
+	ImportSkip      = "skip"
+	ImportOverwrite = "overwrite"
+	ImportRename    = "rename"
+	ImportAdd       = "add"
+)
+
+const (
+	// GlossaryImportComponent is the custom ID prefix of the confirm and
+	// cancel buttons of an import preview. The buttons carry the action and
+	// the staged import ID as arguments.
+	GlossaryImportComponent = "glossaryimport"
+	// GlossaryImportConfirm and GlossaryImportCancel are the button actions.
+	GlossaryImportConfirm = "confirm"
+	GlossaryImportCancel  = "cancel"
+	// MaxGlossaryFileSize caps the size of imported glossary files.
+	MaxGlossaryFileSize = 1 << 20
+	// glossaryImportTTL is how long an import preview can be confirmed.
+	glossaryImportTTL = 15 * time.Minute
+	// importedNote is the revision note of terms added or overwritten by an import.
+	importedNote = "imported"
+)
+
+// GlossaryImportItem is what an import does with one entry.
+type GlossaryImportItem struct {
+	Entry  GlossaryEntry
+	Action string // add, overwrite, rename or skip
+	Title  string // title the entry is imported as
+	Reason string // why the entry is renamed or skipped
+}
+
+// GlossaryImportPlan is the outcome of importing a glossary, worked out
+// before anything is changed.
+type GlossaryImportPlan struct {
+	Conflict string
+	Items    []GlossaryImportItem
+}
+
+// stagedImport is an import waiting for its preview to be confirmed.
+type stagedImport struct {
+	ownerID  string
+	filename string
+	entries  []GlossaryEntry
+	conflict string
+}
+
+// ParseImportConflict returns the conflict policy with the given name.
+func ParseImportConflict(name string) (string, error) {
+	switch name {
+	case ImportSkip, ImportOverwrite, ImportRename:
+		return name, nil
+	default:
+		return "", fmt.Errorf("unknown conflict policy '%s', expected %s, %s or %s", name, ImportSkip, ImportOverwrite, ImportRename)
+	}
+}
+
+// PlanGlossaryImport works out what importing entries would do. Entries
+// whose title is already taken are skipped, overwrite the existing term or
+// are imported under a new title, depending on conflict.
+func (b *Bot) PlanGlossaryImport(entries []GlossaryEntry, conflict string) (GlossaryImportPlan, error) {
+	plan := GlossaryImportPlan{Conflict: conflict}
+	if _, err := ParseImportConflict(conflict); err != nil {
+		return plan, err
+	}
+
+	// Titles claimed by earlier entries of the file
+	claimed := make(map[string]bool)
+	for _, entry := range entries {
+		entry.Term = NormalizeTermTitle(entry.Term)
+		entry.Description = strings.TrimSpace(entry.Description)
+		entry.Category = NormalizeTermTitle(entry.Category)
+		if strings.EqualFold(entry.Category, UncategorizedTerms) {
+			entry.Category = ""
+		}
+		item := GlossaryImportItem{Entry: entry, Action: ImportAdd, Title: entry.Term}
+
+		switch {
+		case !TermNamePattern.MatchString(entry.Term):
+			item.Action, item.Reason = ImportSkip, "invalid title"
+		case entry.Description == "":
+			item.Action, item.Reason = ImportSkip, "no description"
+		case entry.Category != "" && !TermNamePattern.MatchString(entry.Category):
+			item.Action, item.Reason = ImportSkip, "invalid category"
+		default:
+			reason, existing := "", ""
+			if claimed[strings.ToLower(entry.Term)] {
+				reason = "listed earlier in the file"
+			} else {
+				var err error
+				if reason, existing, err = b.termTitleConflict(entry.Term); err != nil {
+					return plan, err
+				}
+			}
+			switch {
+			case reason == "":
+			case conflict == ImportOverwrite && existing != "":
+				item.Action, item.Title = ImportOverwrite, existing
+			case conflict == ImportRename:
+				title, err := b.freeTermTitle(entry.Term, claimed)
+				if err != nil {
+					return plan, err
+				}
+				item.Action, item.Title, item.Reason = ImportRename, title, reason
+			default:
+				item.Action, item.Reason = ImportSkip, reason
+			}
+		}
+
+		if item.Action != ImportSkip {
+			claimed[strings.ToLower(item.Title)] = true
+		}
+		plan.Items = append(plan.Items, item)
+	}
+	return plan, nil
+}
+
+// termTitleConflict explains why a title can't be used for a new term, or
+// returns an empty reason if it can. existing is the title of the term with
+// that title, for conflicts an import may overwrite.
+func (b *Bot) termTitleConflict(title string) (reason, existing string, err error) {
+	t, err := b.FindTerm(title)
+	switch {
+	case err == nil && strings.EqualFold(t.Term, title):
+		return "term exists", t.Term, nil
+	case err == nil:
+		return fmt.Sprintf("alias of term '%s'", t.Term), "", nil
+	case !errors.Is(err, ErrTermNotFound):
+		return "", "", err
+	}
+	if _, err := b.findRemovedTerm(title); err == nil {
+		return "term was removed", "", nil
+	}
+	return "", "", nil
+}
+
+// freeTermTitle returns the first of "title (2)", "title (3)" and so on that
+// is neither used by a term nor claimed by the import.
+func (b *Bot) freeTermTitle(title string, claimed map[string]bool) (string, error) {
+	for n := 2; ; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		candidate := Truncate(title, 64-len(suffix)) + suffix
+		if claimed[strings.ToLower(candidate)] {
+			continue
+		}
+		reason, _, err := b.termTitleConflict(candidate)
+		if err != nil {
+			return "", err
+		}
+		if reason == "" {
+			return candidate, nil
+		}
+	}
+}
+
+// Count returns how many entries the import handles with action.
+func (p GlossaryImportPlan) Count(action string) int {
+	count := 0
+	for _, item := range p.Items {
+		if item.Action == action {
+			count++
+		}
+	}
+	return count
+}
+
+// Summary counts the entries by outcome.
+func (p GlossaryImportPlan) Summary() string {
+	return fmt.Sprintf("%d new, %d overwritten, %d renamed, %d skipped",
+		p.Count(ImportAdd), p.Count(ImportOverwrite), p.Count(ImportRename), p.Count(ImportSkip))
+}
+
+// Lines describes the outcome of every entry, one line each.
+func (p GlossaryImportPlan) Lines() []string {
+	lines := make([]string, len(p.Items))
+	for i, item := range p.Items {
+		switch item.Action {
+		case ImportAdd:
+			lines[i] = fmt.Sprintf("➕ %s", item.Title)
+		case ImportOverwrite:
+			lines[i] = fmt.Sprintf("✏️ %s (overwrites the existing term)", item.Title)
+		case ImportRename:
+			lines[i] = fmt.Sprintf("🔀 %s → %s (%s)", item.Entry.Term, item.Title, item.Reason)
+		default:
+			title := item.Entry.Term
+			if title == "" {
+				title = "(untitled)"
+			}
+			lines[i] = fmt.Sprintf("⏭️ %s: %s", Truncate(title, 80), item.Reason)
+		}
+	}
+	return lines
+}
+
+// ImportGlossary carries out an import plan in a single transaction, so a
+// failed import changes nothing. Imported terms get an "imported" revision
+// authored by actor, and source names the imported file in the audit log.
+// Aliases and triggers that are already used elsewhere are left out and
+// returned.
+func (b *Bot) ImportGlossary(plan GlossaryImportPlan, source string, actor Actor) ([]string, error) {
+	var skipped []string
+	err := b.DB.Transaction(func(tx *gorm.DB) error {
+		skipped = nil
+		termIDs := make([]uint, len(plan.Items))
+
+		// Create and update all terms first, so that aliases can't take the
+		// titles of terms later in the file
+		for i, item := range plan.Items {
+			switch item.Action {
+			case ImportAdd, ImportRename:
+				t := Term{
+					Term:        item.Title,
+					Description: item.Entry.Description,
+					Category:    item.Entry.Category,
+					AuthorID:    actor.ID,
+				}
+				if err := tx.Create(&t).Error; err != nil {
+					return fmt.Errorf("error adding term '%s': %w", item.Title, err)
+				}
+				termIDs[i] = t.ID
+			case ImportOverwrite:
+				var t Term
+				if err := tx.Where("LOWER(term) = ?", strings.ToLower(item.Title)).First(&t).Error; err != nil {
+					return fmt.Errorf("error loading term '%s': %w", item.Title, err)
+				}
+				err := tx.Model(&t).Updates(map[string]interface{}{
+					"description": item.Entry.Description,
+					"category":    item.Entry.Category,
+				}).Error
+				if err != nil {
+					return fmt.Errorf("error overwriting term '%s': %w", item.Title, err)
+				}
+				if err := tx.Where("term_id = ?", t.ID).Delete(&TermAlias{}).Error; err != nil {
+					return err
+				}
+				if err := tx.Where("term_id = ?", t.ID).Delete(&TermTrigger{}).Error; err != nil {
+					return err
+				}
+				termIDs[i] = t.ID
+			}
+		}
+
+		for i, item := range plan.Items {
+			if item.Action == ImportSkip {
+				continue
+			}
+			names, err := importTermNames(tx, termIDs[i], item.Entry)
+			if err != nil {
+				return fmt.Errorf("error importing aliases of term '%s': %w", item.Title, err)
+			}
+			skipped = append(skipped, names...)
+
+			action := TermActionAdd
+			if item.Action == ImportOverwrite {
+				action = TermActionEdit
+			}
+			if err := recordTermRevision(tx, termIDs[i], action, importedNote, actor.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	b.invalidateTermTriggers()
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Error importing glossary")
+		return nil, err
+	}
+	b.GetLogger().WithField("summary", plan.Summary()).Info("Imported glossary")
+	b.audit(actor, "term.import", source, "", plan.Summary())
+	return skipped, nil
+}
+
+// importTermNames adds the aliases and triggers of an entry to a term,
+// returning the ones that are invalid or already used.
+func importTermNames(tx *gorm.DB, termID uint, entry GlossaryEntry) ([]string, error) {
+	var skipped []string
+	for _, alias := range entry.Aliases {
+		alias = NormalizeTermTitle(alias)
+		var taken int64
+		err := tx.Model(&Term{}).Where("LOWER(term) = ?", strings.ToLower(alias)).Count(&taken).Error
+		if err == nil && taken == 0 {
+			err = tx.Model(&TermAlias{}).Where("LOWER(alias) = ?", strings.ToLower(alias)).Count(&taken).Error
+		}
+		if err != nil {
+			return nil, err
+		}
+		if taken > 0 || !TermNamePattern.MatchString(alias) {
+			skipped = append(skipped, alias)
+			continue
+		}
+		if err := tx.Create(&TermAlias{TermID: termID, Alias: alias}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, keyword := range entry.Triggers {
+		keyword = NormalizeTermTitle(keyword)
+		var taken int64
+		if err := tx.Model(&TermTrigger{}).Where("LOWER(keyword) = ?", strings.ToLower(keyword)).Count(&taken).Error; err != nil {
+			return nil, err
+		}
+		if taken > 0 || !TermNamePattern.MatchString(keyword) || len([]rune(keyword)) < 2 {
+			skipped = append(skipped, keyword)
+			continue
+		}
+		if err := tx.Create(&TermTrigger{TermID: termID, Keyword: keyword}).Error; err != nil {
+			return nil, err
+		}
+	}
+	return skipped, nil
+}
+
+// StageGlossaryImport keeps the entries of a file until their import preview
+// is confirmed or cancelled, returning the ID the preview buttons refer to.
+func (b *Bot) StageGlossaryImport(ownerID, filename string, entries []GlossaryEntry, conflict string) string {
+	id := newComponentToken()
+	b.glossaryImports.Set(id, &stagedImport{ownerID: ownerID, filename: filename, entries: entries, conflict: conflict}, glossaryImportTTL)
+	return id
+}
+
+// CheckGlossaryImport returns an error unless a staged import exists and was
+// started by userID.
+func (b *Bot) CheckGlossaryImport(id, userID string) error {
+	_, err := b.stagedImport(id, userID)
+	return err
+}
+
+// ConfirmGlossaryImport carries out a staged import and returns the name of
+// the imported file, the plan and the skipped aliases and triggers. The plan
+// is worked out again, since terms may have changed since the preview.
+func (b *Bot) ConfirmGlossaryImport(id string, actor Actor) (string, GlossaryImportPlan, []string, error) {
+	staged, err := b.stagedImport(id, actor.ID)
+	if err != nil {
+		return "", GlossaryImportPlan{}, nil, err
+	}
+	b.glossaryImports.Delete(id)
+
+	plan, err := b.PlanGlossaryImport(staged.entries, staged.conflict)
+	if err != nil {
+		return staged.filename, plan, nil, err
+	}
+	skipped, err := b.ImportGlossary(plan, staged.filename, actor)
+	return staged.filename, plan, skipped, err
+}
+
+// CancelGlossaryImport drops a staged import.
+func (b *Bot) CancelGlossaryImport(id, userID string) error {
+	if _, err := b.stagedImport(id, userID); err != nil {
+		return err
+	}
+	b.glossaryImports.Delete(id)
+	return nil
+}
+
+func (b *Bot) stagedImport(id, userID string) (*stagedImport, error) {
+	cached, found := b.glossaryImports.Get(id)
+	if !found {
+		return nil, fmt.Errorf("this import has expired, run the command again")
+	}
+	staged := cached.(*stagedImport)
+	if staged.ownerID != userID {
+		return nil, fmt.Errorf("only the person who started this import can confirm or cancel it")
+	}
+	return staged, nil
+}
+
+// FetchGlossaryFile downloads an attached glossary file.
+func (b *Bot) FetchGlossaryFile(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.scrapeClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error downloading file: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxGlossaryFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error downloading file: %w", err)
+	}
+	if len(data) > MaxGlossaryFileSize {
+		return nil, fmt.Errorf("the file is larger than %d KB", MaxGlossaryFileSize/1024)
+	}
+	return data, nil
+}
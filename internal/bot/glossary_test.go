@@ -0,0 +1,45 @@
+// File: internal/bot/glossary_test.go
+
+package bot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGlossaryRoundTrip(t *testing.T) {
+	entries := []GlossaryEntry{
+		{
+			Term:        "Frost Star",
+			Description: "Premium currency.\n\n## Not a category\n### Not a term\n\\## Already escaped",
+			Category:    "Currency",
+			Aliases:     []string{"FS", "stars, frost", "a|b", `back\slash`},
+			Triggers:    []string{"frost star", "fs, please"},
+		},
+		{
+			Term:        "Furnace",
+			Description: "_Also known as: not an alias_\n_Triggers: not a trigger_",
+		},
+		{
+			Term:        "Chief",
+			Description: "You.",
+			Aliases:     []string{"trailing\\"},
+		},
+	}
+
+	for _, format := range GlossaryFormats {
+		t.Run(format, func(t *testing.T) {
+			data, err := EncodeGlossary(entries, format)
+			if err != nil {
+				t.Fatalf("error encoding: %v", err)
+			}
+			decoded, err := DecodeGlossary(data, format)
+			if err != nil {
+				t.Fatalf("error decoding: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, entries) {
+				t.Errorf("round trip changed the glossary:\ngot  %#v\nwant %#v\nencoded:\n%s", decoded, entries, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// File: internal/bot/handlers/audit_handlers.go
+
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"the-keeper/internal/bot"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var userMentionRegex = regexp.MustCompile(`^<@!?(\d+)>$|^(\d{15,20})$`)
+
+func (h *Handler) registerAuditHandlers() {
+	h.bot.RegisterHandler("handleAuditCommand", h.handleAuditCommand)
+}
+
+// Query the audit log: !audit [user|action] [page]
+func (h *Handler) handleAuditCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	var actorID, action string
+	page := 1
+	for _, arg := range args {
+		if match := userMentionRegex.FindStringSubmatch(arg); match != nil {
+			actorID = match[1] + match[2]
+		} else if p, err := strconv.Atoi(arg); err == nil {
+			page = p
+		} else {
+			action = bot.NormalizeInput(arg)
+		}
+	}
+
+	events, err := h.bot.ListAuditEvents(m.GuildID, actorID, action)
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Error listing audit events")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Failed to read the audit log: %v", err))
+		return
+	}
+	if len(events) == 0 {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No audit events found.")
+		return
+	}
+
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = event.Summary()
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "🗒️ Audit log", lines, 10, page)
+}
@@ -7,28 +7,21 @@ package handlers
 //	"github.com/bwmarrin/discordgo"
 //)
 
-// func init() {
-func init() {
-	//	bot.GetBot().RegisterHandler("handleTermCommand", handleTermCommand)
-	//	bot.GetBot().RegisterHandler("handleTermAddCommand", handleTermAddCommand)
-	//	bot.GetBot().RegisterHandler("handleTermEditCommand", handleTermEditCommand)
-	//	bot.GetBot().RegisterHandler("handleTermRemoveCommand", handleTermRemoveCommand)
-	//	bot.GetBot().RegisterHandler("handleTermListCommand", handleTermListCommand)
-	//}
-	//bot.GetBot().RegisterHandler("handleCommandName", handleCommandName)
-	// Register any subcommand handlers here
-	// bot.RegisterHandler("handleCommandNameSubcommand", handleCommandNameSubcommand)
-	//}
-
-	//func handleCommandName(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	// Implement main command logic here
-	//	bot.SendMessage(s, m.ChannelID, "Command not implemented yet.")
-	//}
-
-	// Implement subcommand handlers if any
-	//
-	//	func handleCommandNameSubcommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	//	    // Implement subcommand logic here
-	//	    bot.SendMessage(s, m.ChannelID, "Subcommand not implemented yet.")
-	return
-}
+// Template for a new handler file. Add the register function to RegisterHandlers in handlers.go.
+//
+//	func (h *Handler) registerCommandNameHandlers() {
+//		h.bot.RegisterHandler("handleCommandName", h.handleCommandName)
+//		// Register any subcommand handlers here
+//		h.bot.RegisterHandler("handleCommandNameSubcommand", h.handleCommandNameSubcommand)
+//	}
+//
+//	func (h *Handler) handleCommandName(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+//		// Implement main command logic here
+//		h.bot.SendMessage(s, m.ChannelID, "Command not implemented yet.")
+//	}
+//
+//	// Implement subcommand handlers if any
+//	func (h *Handler) handleCommandNameSubcommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+//		// Implement subcommand logic here
+//		h.bot.SendMessage(s, m.ChannelID, "Subcommand not implemented yet.")
+//	}
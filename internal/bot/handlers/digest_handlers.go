@@ -0,0 +1,61 @@
+// File: internal/bot/handlers/digest_handlers.go
+
+package handlers
+
+import (
+	"fmt"
+	"the-keeper/internal/bot"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *Handler) registerDigestHandlers() {
+	h.bot.RegisterHandler("handleDigestCommand", h.handleDigestCommand)
+	h.bot.RegisterHandler("handleDigestNowCommand", h.handleDigestNowCommand)
+}
+
+// Show the digest schedule, or dispatch to a subcommand
+func (h *Handler) handleDigestCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) > 0 {
+		subCmdName := bot.NormalizeInput(args[0])
+		subCmd, exists := cmd.Subcommand(subCmdName)
+		if !exists {
+			h.sendUnknownSubcommand(s, m, cmd, subCmdName)
+			return
+		}
+		h.runSubcommand(s, m, subCmd, args[1:])
+		return
+	}
+
+	cfg := h.bot.Config.Digest
+	timezone := cfg.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	daily, weekly := "off", "off"
+	if cfg.Daily.Enabled {
+		daily = fmt.Sprintf("every day at %s", cfg.Daily.Time)
+	}
+	if cfg.Weekly.Enabled {
+		weekly = fmt.Sprintf("every %s at %s", cfg.Weekly.Weekday, cfg.Weekly.Time)
+	}
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Digests (%s):\n  daily: %s\n  weekly: %s\nRun `%s` for a digest right away.",
+		timezone, daily, weekly, h.bot.WithPrefix("!digest now")))
+}
+
+// Post a digest of the last day or week to this channel: !digest now [daily|weekly]
+func (h *Handler) handleDigestNowCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	period := bot.DigestDaily
+	if len(args) > 0 {
+		period = bot.NormalizeInput(args[0])
+	}
+	if _, err := bot.DigestDuration(period); err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	if err := h.bot.SendDigest(m.ChannelID, period); err != nil {
+		h.bot.GetLogger().WithError(err).Error("Error sending digest")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Failed to build the digest: %v", err))
+	}
+}
@@ -3,201 +3,155 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"the-keeper/internal/bot"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func init() {
-	bot.RegisterHandlerLater("handleGiftCodeCommand", handleGiftCodeCommand)
-	bot.RegisterHandlerLater("handleGiftCodeRedeemCommand", handleGiftCodeRedeemCommand)
-	bot.RegisterHandlerLater("handleGiftCodeDeployCommand", handleGiftCodeDeployCommand)
-	bot.RegisterHandlerLater("handleGiftCodeValidateCommand", handleGiftCodeValidateCommand)
-	bot.RegisterHandlerLater("handleGiftCodeListCommand", handleGiftCodeListCommand)
-}
-
-// Send help message for gift code commands
-func sendGiftCodeHelp(s *discordgo.Session, channelID string, cmd *bot.Command) {
-	helpMessage := "Available giftcode subcommands:\n"
-	for name, subCmd := range cmd.Subcommands {
-		if !subCmd.Hidden {
-			helpMessage += fmt.Sprintf("  %s: %s\n", name, subCmd.Description)
-			helpMessage += fmt.Sprintf("    Usage: %s\n", subCmd.Usage)
-		}
-	}
-	if err := bot.SendMessage(s, channelID, helpMessage); err != nil {
-		bot.GetBot().GetLogger().WithError(err).Error("Failed to send gift code help message")
-	}
+func (h *Handler) registerGiftCodeHandlers() {
+	h.bot.RegisterHandler("handleGiftCodeCommand", h.handleGiftCodeCommand)
+	h.bot.RegisterHandler("handleGiftCodeRedeemCommand", h.handleGiftCodeRedeemCommand)
+	h.bot.RegisterHandler("handleGiftCodeDeployCommand", h.handleGiftCodeDeployCommand)
+	h.bot.RegisterHandler("handleGiftCodeValidateCommand", h.handleGiftCodeValidateCommand)
+	h.bot.RegisterHandler("handleGiftCodeListCommand", h.handleGiftCodeListCommand)
+	h.bot.RegisterHandler("handleGiftCodeReviewCommand", h.handleGiftCodeReviewCommand)
+	h.bot.RegisterHandler("handleGiftCodeSubmitCommand", h.handleGiftCodeSubmitCommand)
+	h.bot.RegisterComponentHandler(bot.CodeReviewComponent, h.handleCodeReviewComponent)
+	h.bot.RegisterComponentHandler(bot.RedeemComponent, h.handleRedeemComponent)
 }
 
 // Main handler for gift code commands
-func handleGiftCodeCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	if botInstance.GetLogger() == nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
+func (h *Handler) handleGiftCodeCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if h.bot.GetLogger() == nil {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
 		return
 	}
 
 	if len(args) == 0 {
-		sendGiftCodeHelp(s, m.ChannelID, cmd)
+		h.sendCommandHelp(s, m, []string{cmd.Name})
 		return
 	}
 
 	subCmdName := bot.NormalizeInput(args[0])
-	subCmd, exists := cmd.Subcommands[subCmdName]
+	subCmd, exists := cmd.Subcommand(subCmdName)
 	if !exists {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Unknown subcommand. Use !help giftcode to see available subcommands.")
+		h.sendUnknownSubcommand(s, m, cmd, subCmdName)
 		return
 	}
 
 	if subCmd.HandlerFunc != nil {
-		subCmd.HandlerFunc(s, m, args[1:], subCmd)
+		h.runSubcommand(s, m, subCmd, args[1:])
 	} else {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ The subcommand '%s' is not implemented yet.", subCmdName))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ The subcommand '%s' is not implemented yet.", subCmdName))
 	}
 }
 
 // Deploy gift code command handler
-func handleGiftCodeDeployCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	logger := botInstance.GetLogger()
+func (h *Handler) handleGiftCodeDeployCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	logger := h.bot.GetLogger()
 	if logger == nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
 		return
 	}
 
-	if !botInstance.IsAdmin(s, m.GuildID, m.Author.ID) {
-		bot.SendMessage(s, m.ChannelID, "𐄂 You do not have permission to use this command.")
+	if !h.bot.IsAdmin(s, m.GuildID, m.Author.ID) {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 You do not have permission to use this command.")
 		return
 	}
 
 	if len(args) < 1 {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", cmd.Usage))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
 		return
 	}
 
 	giftCode := strings.TrimSpace(args[0]) // Keep the original case, only trim spaces.
-	playerIDs, err := botInstance.GetAllPlayerIDs()
+	h.bot.SendMessage(s, m.ChannelID, "🚀 Deploying gift code to all users...")
+
+	_, total, err := h.bot.DeployGiftCode(giftCode, bot.MessageActor(m), func(result bot.DeployResult) {
+		switch {
+		case result.Err != nil && result.Message != "":
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Gift code redeemed for Player ID %s but failed to record: %v", result.PlayerID, result.Err))
+		case result.Err != nil:
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error for Player ID %s: %v", result.PlayerID, result.Err))
+		default:
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Player ID %s: %s", result.PlayerID, result.Message))
+		}
+	})
 	if err != nil {
-		logger.WithError(err).Error("Error retrieving Player IDs")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error retrieving Player IDs: %v", err))
+		logger.WithError(err).Error("Error deploying gift code")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
 		return
 	}
-
-	if len(playerIDs) == 0 {
-		bot.SendMessage(s, m.ChannelID, "⚠️ No player IDs available for deployment.")
+	if total == 0 {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No player IDs available for deployment.")
 		return
 	}
 
-	bot.SendMessage(s, m.ChannelID, "🚀 Deploying gift code to all users...")
-
-	for discordID, playerID := range playerIDs {
-		success, message, err := botInstance.RedeemGiftCode(playerID, giftCode)
-		if err != nil {
-			logger.WithError(err).
-				WithField("player_id", playerID).
-				WithField("gift_code", giftCode).
-				Error("Error redeeming gift code")
-			bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error for Player ID %s: %v", playerID, err))
-			continue
-		}
-
-		status := "Success"
-		if !success {
-			status = "Failed"
-		}
-
-		err = botInstance.RecordGiftCodeRedemption(discordID, playerID, giftCode, status)
-		if err != nil {
-			logger.WithError(err).Error("Gift code redeemed but failed to record in database")
-			bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Gift code redeemed for Player ID %s but failed to record: %v", playerID, err))
-			continue
-		}
-
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Player ID %s: %s", playerID, message))
-	}
-
-	bot.SendMessage(s, m.ChannelID, "✓ Gift code deployment completed.")
+	h.bot.SendMessage(s, m.ChannelID, "✓ Gift code deployment completed.")
 }
 
 // Redeem gift code command handler
-func handleGiftCodeRedeemCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	if botInstance.GetLogger() == nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
+func (h *Handler) handleGiftCodeRedeemCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if h.bot.GetLogger() == nil {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
 		return
 	}
 
 	if len(args) < 1 {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", cmd.Usage))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
 		return
 	}
 
 	giftCode := strings.TrimSpace(args[0]) // Keep the original case, only trim spaces.
-	playerID, err := botInstance.GetPlayerID(m.Author.ID)
-	if err != nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account.")
-		return
-	}
-
-	success, message, err := botInstance.RedeemGiftCode(playerID, giftCode)
-	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error redeeming gift code")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error redeeming gift code: %v", err))
-		return
-	}
-
-	status := "Success"
-	if !success {
-		status = "Failed"
+	_, message, err := h.bot.RedeemForUser(m.Author.ID, giftCode)
+	switch {
+	case errors.Is(err, bot.ErrNoPlayerID):
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account.")
+	case err != nil:
+		h.bot.GetLogger().WithError(err).Error("Error redeeming gift code")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+	default:
+		h.bot.SendMessage(s, m.ChannelID, message)
 	}
-
-	err = botInstance.RecordGiftCodeRedemption(m.Author.ID, playerID, giftCode, status)
-	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Gift code redeemed but failed to record")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Gift code redeemed but failed to record: %v", err))
-		return
-	}
-
-	bot.SendMessage(s, m.ChannelID, message)
 }
 
 // Validate gift code command handler
-func handleGiftCodeValidateCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	if botInstance.GetLogger() == nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
+func (h *Handler) handleGiftCodeValidateCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if h.bot.GetLogger() == nil {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
 		return
 	}
 
 	if len(args) < 1 {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", cmd.Usage))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
 		return
 	}
 
 	giftCode := strings.TrimSpace(args[0]) // Keep the original case, only trim spaces.
-	playerID, err := botInstance.GetPlayerID(m.Author.ID)
+	playerID, err := h.bot.GetPlayerID(m.Author.ID)
 	if err != nil {
-		bot.SendMessage(s, m.ChannelID, "𐄂 You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account.")
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account.")
 		return
 	}
 
-	isValid, message := botInstance.ValidateGiftCode(giftCode, playerID)
+	isValid, message := h.bot.ValidateGiftCode(giftCode, playerID)
 	if isValid {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Gift code `%s` is valid.", giftCode))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Gift code `%s` is valid.", giftCode))
 	} else {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Invalid gift code: %s", message))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Invalid gift code: %s", message))
 	}
 }
 
 // List all gift code redemptions command handler
-func handleGiftCodeListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	if botInstance.GetLogger() == nil {
-		bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
+func (h *Handler) handleGiftCodeListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if h.bot.GetLogger() == nil {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ Logger is not initialized. Cannot proceed with this operation.")
 		return
 	}
 
@@ -210,37 +164,200 @@ func handleGiftCodeListCommand(s *discordgo.Session, m *discordgo.MessageCreate,
 		}
 	}
 
-	isAdmin := botInstance.IsAdmin(s, m.GuildID, m.Author.ID)
+	isAdmin := h.bot.IsAdmin(s, m.GuildID, m.Author.ID)
 
 	var redemptions []bot.GiftCodeRedemption
 	var err error
 
 	if isAdmin {
-		redemptions, err = botInstance.GetAllGiftCodeRedemptionsPaginated(page, itemsPerPage)
+		redemptions, err = h.bot.GetAllGiftCodeRedemptions()
 	} else {
-		redemptions, err = botInstance.GetUserGiftCodeRedemptionsPaginated(m.Author.ID, page, itemsPerPage)
+		redemptions, err = h.bot.GetUserGiftCodeRedemptions(m.Author.ID)
 	}
 
 	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error retrieving gift codes")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error retrieving gift codes: %v", err))
+		h.bot.GetLogger().WithError(err).Error("Error retrieving gift codes")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error retrieving gift codes: %v", err))
 		return
 	}
 
 	if len(redemptions) == 0 {
-		bot.SendMessage(s, m.ChannelID, "⚠️ No gift codes found for this page.")
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No gift codes found.")
 		return
 	}
 
-	message := fmt.Sprintf("📜 Gift code redemptions (Page %d):\n", page)
+	lines := make([]string, 0, len(redemptions))
 	for _, r := range redemptions {
 		if isAdmin {
-			message += fmt.Sprintf("Discord ID: %s, Player ID: %s, Code: %s, Status: %s\n", r.DiscordID, r.PlayerID, r.GiftCode, r.Status)
+			lines = append(lines, fmt.Sprintf("Discord ID: %s, Player ID: %s, Code: %s, Status: %s", r.DiscordID, r.PlayerID, r.GiftCode, r.Status))
 		} else {
-			message += fmt.Sprintf("Code: %s, Status: %s\n", r.GiftCode, r.Status)
+			lines = append(lines, fmt.Sprintf("Code: %s, Status: %s", r.GiftCode, r.Status))
 		}
 	}
-	message += fmt.Sprintf("\nUse '!giftcode list %d' to see the next page", page+1)
 
-	bot.SendMessage(s, m.ChannelID, message)
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "📜 Gift code redemptions", lines, itemsPerPage, page)
+}
+
+// Review discovered gift codes command handler
+func (h *Handler) handleGiftCodeReviewCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) == 0 {
+		codes, err := h.bot.ListPendingCodes()
+		if err != nil {
+			h.bot.GetLogger().WithError(err).Error("Error retrieving pending gift codes")
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Error retrieving pending gift codes: %v", err))
+			return
+		}
+		if len(codes) == 0 {
+			h.bot.SendMessage(s, m.ChannelID, "✓ No gift codes are waiting for review.")
+			return
+		}
+
+		lines := make([]string, 0, len(codes))
+		for _, code := range codes {
+			lines = append(lines, fmt.Sprintf("`%s` %s from %s (first seen %s)",
+				code.Code, bot.FormatConfidence(code.Confidence), code.SourceList(), code.FirstSeenAt.Format("2006-01-02 15:04")))
+		}
+		h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "🔎 Gift codes awaiting review", lines, 10, 1)
+		return
+	}
+
+	if len(args) < 2 {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+
+	action := bot.NormalizeInput(args[0])
+	if action != bot.CodeReviewApprove && action != bot.CodeReviewDeploy && action != bot.CodeReviewReject {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+	approve := action != bot.CodeReviewReject
+
+	giftCode := strings.TrimSpace(args[1])
+	ctx, cancel := context.WithTimeout(h.bot.Context(), time.Minute)
+	defer cancel()
+	record, err := h.bot.ReviewDiscoveredCode(ctx, giftCode, approve, action == bot.CodeReviewDeploy, bot.MessageActor(m))
+	if err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	if approve {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Gift code `%s` approved and announced.", record.Code))
+	} else {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Gift code `%s` rejected.", record.Code))
+	}
+}
+
+// Submit a gift code for admin review command handler
+func (h *Handler) handleGiftCodeSubmitCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+
+	giftCode := strings.TrimSpace(args[0]) // Keep the original case, only trim spaces.
+	description := strings.Join(args[1:], " ")
+	playerID, err := h.bot.GetPlayerID(m.Author.ID)
+	if err != nil {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account.")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(h.bot.Context(), time.Minute)
+	defer cancel()
+	record, err := h.bot.SubmitGiftCode(ctx, giftCode, description, playerID, m.ChannelID, bot.MessageActor(m))
+	if err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Thanks! Gift code `%s` was submitted for review.", record.Code))
+}
+
+// Handle the approve, deploy and reject buttons of a queued gift code
+func (h *Handler) handleCodeReviewComponent(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 2 {
+		return
+	}
+	userID := bot.InteractionUserID(i)
+	if !h.bot.IsAdmin(s, i.GuildID, userID) {
+		h.bot.RespondEphemeral(s, i, "𐄂 Only admins can review gift codes.")
+		return
+	}
+
+	action, giftCode := args[0], args[1]
+	var approve, deploy bool
+	var outcome string
+	switch action {
+	case bot.CodeReviewApprove:
+		approve = true
+		outcome = fmt.Sprintf("✓ Approved by <@%s>", userID)
+	case bot.CodeReviewDeploy:
+		approve, deploy = true, true
+		outcome = fmt.Sprintf("✓ Approved and deployed by <@%s>", userID)
+	case bot.CodeReviewReject:
+		outcome = fmt.Sprintf("𐄂 Rejected by <@%s>", userID)
+	default:
+		h.bot.RespondEphemeral(s, i, fmt.Sprintf("𐄂 Unknown review action '%s'.", action))
+		return
+	}
+
+	// Reviewing waits for running scrapes and may validate the code, which
+	// can take longer than Discord waits for an answer
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to defer gift code review")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(h.bot.Context(), time.Minute)
+	defer cancel()
+	record, err := h.bot.ReviewDiscoveredCode(ctx, giftCode, approve, deploy, bot.InteractionActor(i))
+	if err != nil {
+		h.bot.FollowupEphemeral(s, i, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	embed := record.ReviewEmbed()
+	embed.Title = "Gift code reviewed"
+	embeds := []*discordgo.MessageEmbed{embed}
+	components := []discordgo.MessageComponent{}
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &outcome,
+		Embeds:     &embeds,
+		Components: &components,
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to update gift code review message")
+	}
+}
+
+// Handle the "Redeem for me" button of a new gift code announcement
+func (h *Handler) handleRedeemComponent(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 1 {
+		return
+	}
+	giftCode := args[0]
+
+	// Redeeming takes longer than the interaction deadline, so answer later
+	if err := h.bot.DeferEphemeral(s, i); err != nil {
+		return
+	}
+
+	success, message, err := h.bot.RedeemForUser(bot.InteractionUserID(i), giftCode)
+	switch {
+	case errors.Is(err, bot.ErrNoPlayerID):
+		message = "⚠️ You do not have a Player ID associated. Use `!id add <PlayerID>` to associate your account."
+	case err != nil:
+		h.bot.GetLogger().WithError(err).WithField("gift_code", giftCode).Error("Error redeeming gift code from button")
+		message = fmt.Sprintf("𐄂 %v", err)
+	case success:
+		message = fmt.Sprintf("✓ `%s`: %s", giftCode, message)
+	default:
+		message = fmt.Sprintf("𐄂 `%s`: %s", giftCode, message)
+	}
+	h.bot.EditInteractionResponse(s, i, message)
 }
@@ -6,12 +6,38 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// Handler binds the command handlers to the bot instance they operate on
+type Handler struct {
+	bot *bot.Bot
+}
+
 // RegisterHandlers registers all command handlers with the bot instance
 func RegisterHandlers(b *bot.Bot) {
-	b.RegisterHandler("handleCommandName", handleCommandName)
+	h := &Handler{bot: b}
+	h.bot.RegisterHandler("handleCommandName", h.handleCommandName)
+	h.registerPlaceholderHandlers()
+	h.registerHelpHandlers()
+	h.registerIDHandlers()
+	h.registerTermHandlers()
+	h.registerGiftCodeHandlers()
+	h.registerScrapeHandlers()
+	h.registerSettingsHandlers()
+	h.registerAuditHandlers()
+	h.registerNotifyHandlers()
+	h.registerDigestHandlers()
 	// Register any other handlers here...
 }
 
-func handleCommandName(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	bot.SendMessage(s, m.ChannelID, "Command not implemented yet.")
+func (h *Handler) handleCommandName(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	h.bot.SendMessage(s, m.ChannelID, "Command not implemented yet.")
+}
+
+// runSubcommand runs a subcommand after checking its own permission, since
+// HandleCommand only checks the permission of the top-level command.
+func (h *Handler) runSubcommand(s *discordgo.Session, m *discordgo.MessageCreate, subCmd *bot.Command, args []string) {
+	if !h.bot.CanRun(s, m.GuildID, m.Author.ID, subCmd) {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 You do not have permission to use this command.")
+		return
+	}
+	subCmd.HandlerFunc(s, m, args, subCmd)
 }
@@ -4,85 +4,168 @@ package handlers
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"the-keeper/internal/bot"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func init() {
-	bot.RegisterHandlerLater("handleHelpCommand", handleHelpCommand)
-	bot.RegisterHandlerLater("handleDumpDatabaseCommand", handleDumpDatabaseCommand) // New command registration
+func (h *Handler) registerHelpHandlers() {
+	h.bot.RegisterHandler("handleHelpCommand", h.handleHelpCommand)
+	h.bot.RegisterHandler("handleDumpDatabaseCommand", h.handleDumpDatabaseCommand) // New command registration
 }
 
-func handleHelpCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) handleHelpCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) == 0 {
-		sendGeneralHelp(s, m.ChannelID)
+		h.sendGeneralHelp(s, m)
 	} else {
-		sendCommandHelp(s, m.ChannelID, args[0])
+		h.sendCommandHelp(s, m, args)
 	}
 }
 
-func sendGeneralHelp(s *discordgo.Session, channelID string) {
-	var helpMessage strings.Builder
-	helpMessage.WriteString("Available commands:\n")
-	for _, cmd := range bot.CommandRegistry {
-		if !cmd.Hidden {
-			helpMessage.WriteString(fmt.Sprintf("!%s: %s\n", cmd.Name, cmd.Description))
+// visibleCommands returns the commands the caller may see, ordered by name
+func (h *Handler) visibleCommands(s *discordgo.Session, m *discordgo.MessageCreate, commands map[string]*bot.Command) []*bot.Command {
+	var visible []*bot.Command
+	roles := h.bot.MemberRoles(s, m.GuildID, m.Author.ID)
+	for _, cmd := range bot.SortedCommands(commands) {
+		if cmd.Hidden || !h.bot.CanRunWithRoles(roles, cmd) {
+			continue
 		}
+		visible = append(visible, cmd)
+	}
+	return visible
+}
+
+func (h *Handler) sendGeneralHelp(s *discordgo.Session, m *discordgo.MessageCreate) {
+	byCategory := make(map[string][]*bot.Command)
+	for _, cmd := range h.visibleCommands(s, m, h.bot.Commands()) {
+		byCategory[cmd.Category] = append(byCategory[cmd.Category], cmd)
 	}
-	helpMessage.WriteString("\nUse !help <command> for more information on a specific command.")
 
-	if err := bot.SendMessage(s, channelID, helpMessage.String()); err != nil {
-		bot.GetBot().GetLogger().WithError(err).Error("Failed to send general help message")
+	embed := &discordgo.MessageEmbed{
+		Title:  "Available commands",
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Use %s for more information on a specific command.", h.bot.WithPrefix("!help <command> [subcommand]"))},
+	}
+
+	addField := func(title string, commands []*bot.Command) {
+		if len(commands) == 0 {
+			return
+		}
+		lines := make([]string, len(commands))
+		for i, cmd := range commands {
+			lines[i] = fmt.Sprintf("`%s` — %s", h.bot.WithPrefix(cmd.Name), cmd.Description)
+		}
+		embed.Fields = append(embed.Fields, bot.EmbedFields(title, lines)...)
+	}
+
+	for _, category := range h.bot.Categories() {
+		title := category.Title
+		if title == "" {
+			title = category.Name
+		}
+		addField(title, byCategory[category.Name])
+		delete(byCategory, category.Name)
+	}
+
+	// Commands without a declared category are listed last
+	var uncategorized []*bot.Command
+	for _, commands := range byCategory {
+		uncategorized = append(uncategorized, commands...)
+	}
+	sort.Slice(uncategorized, func(i, j int) bool { return uncategorized[i].Name < uncategorized[j].Name })
+	addField("Other", uncategorized)
+
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to send general help message")
 	}
 }
 
-func sendCommandHelp(s *discordgo.Session, channelID string, commandName string) {
-	cmd, exists := bot.CommandRegistry[commandName]
-	if !exists || cmd.Hidden {
-		if err := bot.SendMessage(s, channelID, "Unknown command."); err != nil {
-			bot.GetBot().GetLogger().WithError(err).Error("Failed to send unknown command message")
+func (h *Handler) sendCommandHelp(s *discordgo.Session, m *discordgo.MessageCreate, path []string) {
+	cmd, exists := h.bot.GetCommand(bot.NormalizeInput(path[0]))
+	if !exists || cmd.Hidden || !h.bot.CanRun(s, m.GuildID, m.Author.ID, cmd) {
+		response := "Unknown command."
+		if h.bot.SuggestionsEnabled(m.GuildID) {
+			if suggestion := bot.FormatSuggestions("", h.bot.SuggestCommands(h.bot.MemberRoles(s, m.GuildID, m.Author.ID), path[0])); suggestion != "" {
+				response += " " + suggestion
+			}
 		}
+		h.bot.SendMessage(s, m.ChannelID, response)
 		return
 	}
 
-	var helpMessage strings.Builder
-	helpMessage.WriteString(fmt.Sprintf("Help for !%s:\n", cmd.Name))
-	helpMessage.WriteString(fmt.Sprintf("Description: %s\n", cmd.Description))
-	helpMessage.WriteString(fmt.Sprintf("Usage: %s\n", cmd.Usage))
+	name := cmd.Name
+	if len(path) > 1 {
+		subCmd, exists := cmd.Subcommand(bot.NormalizeInput(path[1]))
+		if !exists || subCmd.Hidden || !h.bot.CanRun(s, m.GuildID, m.Author.ID, subCmd) {
+			h.sendUnknownSubcommand(s, m, cmd, path[1])
+			return
+		}
+		name += " " + subCmd.Name
+		cmd = subCmd
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("Help for %s", h.bot.WithPrefix(name)),
+		Description: cmd.Description,
+	}
+	if cmd.Usage != "" {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Usage", Value: fmt.Sprintf("`%s`", h.bot.WithPrefix(cmd.Usage))})
+	}
 	if cmd.Cooldown != "" {
-		helpMessage.WriteString(fmt.Sprintf("Cooldown: %s\n", cmd.Cooldown))
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Cooldown", Value: cmd.Cooldown, Inline: true})
 	}
-	if len(cmd.Subcommands) > 0 {
-		helpMessage.WriteString("Subcommands:\n")
-		for _, subCmd := range cmd.Subcommands {
-			if !subCmd.Hidden {
-				helpMessage.WriteString(fmt.Sprintf("  %s: %s\n", subCmd.Name, subCmd.Description))
-			}
+	permission := "Everyone"
+	if cmd.Permission == "admin" {
+		permission = "Admins only"
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Permissions", Value: permission, Inline: true})
+	if len(cmd.Examples) > 0 {
+		var examples strings.Builder
+		for _, example := range cmd.Examples {
+			examples.WriteString(fmt.Sprintf("`%s`\n", h.bot.WithPrefix(example)))
 		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "Examples", Value: examples.String()})
+	}
+	if subCommands := h.visibleCommands(s, m, cmd.Subcommands); len(subCommands) > 0 {
+		lines := make([]string, len(subCommands))
+		for i, subCmd := range subCommands {
+			lines[i] = fmt.Sprintf("`%s` — %s", subCmd.Name, subCmd.Description)
+		}
+		embed.Fields = append(embed.Fields, bot.EmbedFields("Subcommands", lines)...)
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Use %s for details on a subcommand.", h.bot.WithPrefix("!help "+name+" <subcommand>"))}
 	}
 
-	if err := bot.SendMessage(s, channelID, helpMessage.String()); err != nil {
-		bot.GetBot().GetLogger().WithError(err).Error("Failed to send command help message")
+	if _, err := s.ChannelMessageSendEmbed(m.ChannelID, embed); err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to send command help message")
 	}
 }
 
+// sendUnknownSubcommand tells the caller the subcommand doesn't exist, listing close matches
+func (h *Handler) sendUnknownSubcommand(s *discordgo.Session, m *discordgo.MessageCreate, cmd *bot.Command, name string) {
+	response := fmt.Sprintf("⚠️ Unknown subcommand. Use `%s` to see available subcommands.", h.bot.WithPrefix("!help "+cmd.Name))
+	if h.bot.SuggestionsEnabled(m.GuildID) {
+		if suggestion := bot.FormatSuggestions(h.bot.WithPrefix(cmd.Name+" "), h.bot.SuggestSubcommands(h.bot.MemberRoles(s, m.GuildID, m.Author.ID), cmd, name)); suggestion != "" {
+			response += "\n" + suggestion
+		}
+	}
+	h.bot.SendMessage(s, m.ChannelID, response)
+}
+
 // Dump the entire database (hidden, authorized command)
-func handleDumpDatabaseCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	if !botInstance.IsAdmin(s, m.GuildID, m.Author.ID) {
+func (h *Handler) handleDumpDatabaseCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if !h.bot.IsAdmin(s, m.GuildID, m.Author.ID) {
 		s.ChannelMessageSend(m.ChannelID, "𐄂 You do not have permission to use this command.")
 		return
 	}
 
-	// Dump Terms table
-	terms, err := botInstance.ListTerms()
+	terms, players, err := h.bot.DumpDatabase(bot.MessageActor(m))
 	if err != nil {
-		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to list terms: %v", err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to dump the database: %v", err))
 		return
 	}
 
+	// Dump Terms table
 	if len(terms) == 0 {
 		s.ChannelMessageSend(m.ChannelID, "⚠️ No terms available in the database.")
 	} else {
@@ -93,16 +176,10 @@ func handleDumpDatabaseCommand(s *discordgo.Session, m *discordgo.MessageCreate,
 		for _, term := range terms {
 			response.WriteString(fmt.Sprintf("| %s | %s |\n", term.Term, term.Description))
 		}
-		s.ChannelMessageSend(m.ChannelID, response.String())
+		h.bot.SendLongMessage(s, m.ChannelID, response.String())
 	}
 
 	// Dump Player IDs table
-	players, err := botInstance.ListPlayers()
-	if err != nil {
-		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to list players: %v", err))
-		return
-	}
-
 	if len(players) == 0 {
 		s.ChannelMessageSend(m.ChannelID, "⚠️ No players available in the database.")
 	} else {
@@ -113,6 +190,6 @@ func handleDumpDatabaseCommand(s *discordgo.Session, m *discordgo.MessageCreate,
 		for _, player := range players {
 			response.WriteString(fmt.Sprintf("| %s | %s |\n", player.DiscordID, player.PlayerID))
 		}
-		s.ChannelMessageSend(m.ChannelID, response.String())
+		h.bot.SendLongMessage(s, m.ChannelID, response.String())
 	}
 }
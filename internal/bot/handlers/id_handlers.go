@@ -4,7 +4,6 @@ package handlers
 import (
 	"fmt"
 	"regexp"
-	"strings"
 	"the-keeper/internal/bot"
 
 	"github.com/bwmarrin/discordgo"
@@ -15,58 +14,39 @@ var (
 	playerIDRegex = regexp.MustCompile(`^\d{3,12}$`)
 )
 
-func init() {
-	bot.RegisterHandlerLater("handleIDCommand", handleIDCommand)
-	bot.RegisterHandlerLater("handleIDAddCommand", handleIDAddCommand)
-	bot.RegisterHandlerLater("handleIDEditCommand", handleIDEditCommand)
-	bot.RegisterHandlerLater("handleIDRemoveCommand", handleIDRemoveCommand)
-	bot.RegisterHandlerLater("handleIDListCommand", handleIDListCommand)
+func (h *Handler) registerIDHandlers() {
+	h.bot.RegisterHandler("handleIDCommand", h.handleIDCommand)
+	h.bot.RegisterHandler("handleIDAddCommand", h.handleIDAddCommand)
+	h.bot.RegisterHandler("handleIDEditCommand", h.handleIDEditCommand)
+	h.bot.RegisterHandler("handleIDRemoveCommand", h.handleIDRemoveCommand)
+	h.bot.RegisterHandler("handleIDListCommand", h.handleIDListCommand)
 }
 
-func handleIDCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) handleIDCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) == 0 {
-		sendIDHelp(s, m.ChannelID, cmd)
+		h.sendCommandHelp(s, m, []string{cmd.Name})
 		return
 	}
 
 	subCmdName := args[0]
-	subCmd, exists := cmd.Subcommands[subCmdName]
+	subCmd, exists := cmd.Subcommand(bot.NormalizeInput(subCmdName))
 	if !exists {
-		bot.SendMessage(s, m.ChannelID, "Unknown subcommand. Use `!help id` to see available subcommands.")
+		h.sendUnknownSubcommand(s, m, cmd, subCmdName)
 		return
 	}
 
 	if subCmd.HandlerFunc != nil {
-		subCmd.HandlerFunc(s, m, args[1:], subCmd)
+		h.runSubcommand(s, m, subCmd, args[1:])
 	} else {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("The subcommand `%s` is not implemented yet.", subCmdName))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("The subcommand `%s` is not implemented yet.", subCmdName))
 	}
 }
 
-func sendIDHelp(s *discordgo.Session, channelID string, cmd *bot.Command) {
-	var helpMsg strings.Builder
-	helpMsg.WriteString("Available ID subcommands:\n")
-	for subName, subCmd := range cmd.Subcommands {
-		if !subCmd.Hidden {
-			helpMsg.WriteString(fmt.Sprintf("  %s: %s\n", subName, subCmd.Description))
-			helpMsg.WriteString(fmt.Sprintf("    Usage: %s\n", subCmd.Usage))
-			if subCmd.Cooldown != "" {
-				helpMsg.WriteString(fmt.Sprintf("    Cooldown: %s\n", subCmd.Cooldown))
-			}
-			helpMsg.WriteString("\n")
-		}
-	}
-	if err := bot.SendMessage(s, channelID, helpMsg.String()); err != nil {
-		bot.GetBot().GetLogger().WithError(err).Error("Failed to send ID help message")
-	}
-}
-
-func handleIDAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	isAuthorized := botInstance.IsAdmin(s, m.GuildID, m.Author.ID)
+func (h *Handler) handleIDAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	isAuthorized := h.bot.IsAdmin(s, m.GuildID, m.Author.ID)
 
 	// Add this logging
-	botInstance.GetLogger().WithFields(logrus.Fields{
+	h.bot.GetLogger().WithFields(logrus.Fields{
 		"user_id":       m.Author.ID,
 		"guild_id":      m.GuildID,
 		"is_authorized": isAuthorized,
@@ -89,86 +69,81 @@ func handleIDAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args [
 		if isAuthorized {
 			usage = "Usage: !id add <discordID> <playerID>"
 		}
-		bot.SendMessage(s, m.ChannelID, usage)
+		h.bot.SendMessage(s, m.ChannelID, usage)
 		return
 	}
 
 	if !playerIDRegex.MatchString(playerID) {
-		bot.SendMessage(s, m.ChannelID, "𐄂 Invalid playerID. It should be a number between 3 and 12 digits.")
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Invalid playerID. It should be a number between 3 and 12 digits.")
 		return
 	}
 
-	err := botInstance.DB.Create(&bot.Player{DiscordID: discordID, PlayerID: playerID}).Error
+	err := h.bot.AddPlayerID(discordID, playerID, bot.MessageActor(m))
 	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error adding player ID")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error adding player ID: %v", err))
+		h.bot.GetLogger().WithError(err).Error("Error adding player ID")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error adding player ID: %v", err))
 		return
 	}
 
 	if discordID == m.Author.ID {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Player ID %s has been added for you.", playerID))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Player ID %s has been added for you.", playerID))
 	} else {
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Player ID %s has been added for Discord ID %s.", playerID, discordID))
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Player ID %s has been added for Discord ID %s.", playerID, discordID))
 	}
 }
 
-func handleIDEditCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) handleIDEditCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) < 1 {
-		bot.SendMessage(s, m.ChannelID, "Usage: !id edit <newPlayerID>")
+		h.bot.SendMessage(s, m.ChannelID, "Usage: !id edit <newPlayerID>")
 		return
 	}
 	newPlayerID := args[0]
 	if !playerIDRegex.MatchString(newPlayerID) {
-		bot.SendMessage(s, m.ChannelID, "𐄂 Invalid playerID. It should be a number between 3 and 12 digits.")
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Invalid playerID. It should be a number between 3 and 12 digits.")
 		return
 	}
-
-	botInstance := bot.GetBot()
-	err := botInstance.DB.Model(&bot.Player{}).Where("discord_id = ?", m.Author.ID).Update("player_id", newPlayerID).Error
+	err := h.bot.EditPlayerID(m.Author.ID, newPlayerID, bot.MessageActor(m))
 	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error editing player ID")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error editing player ID: %v", err))
+		h.bot.GetLogger().WithError(err).Error("Error editing player ID")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error editing player ID: %v", err))
 		return
 	}
-	bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Your player ID has been updated to %s.", newPlayerID))
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Your player ID has been updated to %s.", newPlayerID))
 }
 
-func handleIDRemoveCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
-	err := botInstance.DB.Where("discord_id = ?", m.Author.ID).Delete(&bot.Player{}).Error
+func (h *Handler) handleIDRemoveCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	err := h.bot.RemovePlayerID(m.Author.ID, bot.MessageActor(m))
 	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error removing player ID")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error removing player ID: %v", err))
+		h.bot.GetLogger().WithError(err).Error("Error removing player ID")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error removing player ID: %v", err))
 		return
 	}
-	bot.SendMessage(s, m.ChannelID, "✓ Your player ID association has been removed.")
+	h.bot.SendMessage(s, m.ChannelID, "✓ Your player ID association has been removed.")
 }
 
-func handleIDListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
+func (h *Handler) handleIDListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	var players []bot.Player
-	err := botInstance.DB.Order("discord_id").Find(&players).Error
+	err := h.bot.DB.Order("discord_id").Find(&players).Error
 	if err != nil {
-		botInstance.GetLogger().WithError(err).Error("Error listing players")
-		bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error listing players: %v", err))
+		h.bot.GetLogger().WithError(err).Error("Error listing players")
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ Error listing players: %v", err))
 		return
 	}
 	if len(players) == 0 {
-		bot.SendMessage(s, m.ChannelID, "⚠️ No player IDs have been registered.")
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No player IDs have been registered.")
 		return
 	}
 
-	var response strings.Builder
-	response.WriteString("Player ID List:\n")
+	lines := make([]string, 0, len(players))
 	for _, player := range players {
 		user, err := s.User(player.DiscordID)
 		username := "Unknown User"
 		if err == nil {
 			username = user.Username
 		}
-		response.WriteString(fmt.Sprintf("%s: %s\n", username, player.PlayerID))
+		lines = append(lines, fmt.Sprintf("%s: %s", username, player.PlayerID))
 	}
-	if err := bot.SendMessage(s, m.ChannelID, response.String()); err != nil {
-		botInstance.GetLogger().WithError(err).Error("Failed to send player ID list")
+	if err := h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "Player ID List", lines, 20, 1); err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to send player ID list")
 	}
 }
@@ -0,0 +1,225 @@
+// File: internal/bot/handlers/notify_handlers.go
+
+package handlers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"the-keeper/internal/bot"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var (
+	channelMentionRegex = regexp.MustCompile(`^<#(\d+)>$|^(\d{15,20})$`)
+	roleMentionRegex    = regexp.MustCompile(`^<@&(\d+)>$|^(\d{15,20})$`)
+)
+
+func (h *Handler) registerNotifyHandlers() {
+	h.bot.RegisterHandler("handleNotifyCommand", h.handleNotifyCommand)
+	h.bot.RegisterHandler("handleNotifyRouteCommand", h.handleNotifyRouteCommand)
+	h.bot.RegisterHandler("handleNotifyDMCommand", h.handleNotifyDMCommand)
+}
+
+// Main handler for notification commands
+func (h *Handler) handleNotifyCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) == 0 {
+		h.sendCommandHelp(s, m, []string{cmd.Name})
+		return
+	}
+
+	subCmdName := bot.NormalizeInput(args[0])
+	subCmd, exists := cmd.Subcommand(subCmdName)
+	if !exists {
+		h.sendUnknownSubcommand(s, m, cmd, subCmdName)
+		return
+	}
+
+	if subCmd.HandlerFunc != nil {
+		h.runSubcommand(s, m, subCmd, args[1:])
+	} else {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ The subcommand '%s' is not implemented yet.", subCmdName))
+	}
+}
+
+// Manage notification routes: !notify route [list|add|remove|test]
+func (h *Handler) handleNotifyRouteCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	action := "list"
+	if len(args) > 0 {
+		action = bot.NormalizeInput(args[0])
+		args = args[1:]
+	}
+
+	switch action {
+	case "list":
+		h.listNotificationRoutes(s, m)
+	case "add":
+		h.addNotificationRoute(s, m, args, cmd)
+	case "remove":
+		id, ok := parseRouteID(args)
+		if !ok {
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+			return
+		}
+		if _, err := h.bot.RemoveNotificationRoute(id, bot.MessageActor(m)); err != nil {
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+			return
+		}
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Notification route %d removed.", id))
+	case "test":
+		h.testNotificationRoute(s, m, args, cmd)
+	default:
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+	}
+}
+
+// Turn DM notifications on or off: !notify dm [on|off]
+func (h *Handler) handleNotifyDMCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	settings := h.bot.GetUserNotificationSettings(m.Author.ID)
+	if len(args) == 0 {
+		switch {
+		case !settings.DMEnabled:
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("DM notifications are off. Turn them on with `%s`.", h.bot.WithPrefix("!notify dm on")))
+		case settings.DMPaused:
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("⚠️ DM notifications are paused because DMs to you failed: %s\nOpen your DMs and run `%s` to resume them.",
+				settings.LastDMError, h.bot.WithPrefix("!notify dm on")))
+		default:
+			h.bot.SendMessage(s, m.ChannelID, "✓ DM notifications are on.")
+		}
+		return
+	}
+
+	enabled, ok := parseOnOff(args[0])
+	if !ok {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+
+	if err := h.bot.SetDMNotifications(enabled, bot.MessageActor(m)); err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	if enabled {
+		h.bot.SendMessage(s, m.ChannelID, "✓ You'll get a DM when a new code is found and when a code is redeemed for your account.")
+	} else {
+		h.bot.SendMessage(s, m.ChannelID, "✓ DM notifications turned off.")
+	}
+}
+
+func (h *Handler) listNotificationRoutes(s *discordgo.Session, m *discordgo.MessageCreate) {
+	routes, err := h.bot.NotificationRoutes()
+	if err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	if len(routes) == 0 {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No notification routes are configured.")
+		return
+	}
+
+	lines := make([]string, 0, len(routes))
+	for _, route := range routes {
+		id := "config"
+		if route.ID != 0 {
+			id = strconv.FormatUint(uint64(route.ID), 10)
+		}
+		lines = append(lines, fmt.Sprintf("`%s` %s", id, route.Describe()))
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "📣 Notification routes", lines, 10, 1)
+}
+
+// addNotificationRoute parses: <event> <target> <destination...> [template]
+// where the destination is a channel, a channel and a role, user mentions or a URL.
+func (h *Handler) addNotificationRoute(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 3 {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+
+	route := bot.NotificationRoute{
+		Event:     bot.NormalizeInput(args[0]),
+		Target:    bot.NormalizeInput(args[1]),
+		CreatedBy: m.Author.ID,
+	}
+	rest := args[2:]
+	switch route.Target {
+	case bot.RouteTargetChannel:
+		route.ChannelID = mentionID(channelMentionRegex, rest[0])
+		rest = rest[1:]
+	case bot.RouteTargetRole:
+		route.ChannelID = mentionID(channelMentionRegex, rest[0])
+		if len(rest) > 1 {
+			route.RoleID = mentionID(roleMentionRegex, rest[1])
+			rest = rest[2:]
+		} else {
+			rest = nil
+		}
+	case bot.RouteTargetDM:
+		for len(rest) > 0 {
+			userID := mentionID(userMentionRegex, rest[0])
+			if userID == "" {
+				break
+			}
+			route.UserIDs = append(route.UserIDs, userID)
+			rest = rest[1:]
+		}
+	case bot.RouteTargetWebhook:
+		route.URL = rest[0]
+		rest = rest[1:]
+	}
+	route.Template = strings.Join(rest, " ")
+
+	if err := h.bot.AddNotificationRoute(&route, bot.MessageActor(m)); err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Notification route %d added: %s", route.ID, route.Describe()))
+}
+
+// testNotificationRoute sends an example event to a stored route.
+func (h *Handler) testNotificationRoute(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	id, ok := parseRouteID(args)
+	if !ok {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+	routes, err := h.bot.NotificationRoutes()
+	if err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	for _, route := range routes {
+		if route.ID != id {
+			continue
+		}
+		if err := h.bot.DeliverNotification(route, h.bot.SampleNotification(route.Event)); err != nil {
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Test notification failed: %v", err))
+			return
+		}
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ Test notification sent to route %d.", id))
+		return
+	}
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 No notification route with ID %d", id))
+}
+
+func parseRouteID(args []string) (uint, bool) {
+	if len(args) < 1 {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// mentionID returns the ID in a mention or raw ID matched by regex, or "".
+func mentionID(regex *regexp.Regexp, arg string) string {
+	match := regex.FindStringSubmatch(arg)
+	if match == nil {
+		return ""
+	}
+	return match[1] + match[2]
+}
@@ -9,14 +9,14 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-func init() {
-	bot.RegisterHandlerLater("placeholderHandler", PlaceholderHandler)
+func (h *Handler) registerPlaceholderHandlers() {
+	h.bot.RegisterHandler("placeholderHandler", h.PlaceholderHandler)
 }
 
 // PlaceholderHandler is used for commands that are not yet implemented
-func PlaceholderHandler(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) PlaceholderHandler(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	response := fmt.Sprintf("The command '%s' is not implemented yet... stay tuned!", cmd.Name)
-	if err := bot.SendMessage(s, m.ChannelID, response); err != nil {
-		bot.GetBot().GetLogger().WithError(err).Error("Failed to send placeholder message")
+	if err := h.bot.SendMessage(s, m.ChannelID, response); err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to send placeholder message")
 	}
 }
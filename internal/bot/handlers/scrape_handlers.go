@@ -12,25 +12,70 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-func init() {
-	bot.RegisterHandlerLater("handleScrapeCommand", handleScrapeCommand)
+func (h *Handler) registerScrapeHandlers() {
+	h.bot.RegisterHandler("handleScrapeCommand", h.handleScrapeCommand)
+	h.bot.RegisterHandler("handleScrapeHealthCommand", h.handleScrapeHealthCommand)
+	h.bot.RegisterHandler("handleScrapeRecordCommand", h.handleScrapeRecordCommand)
 }
 
-func handleScrapeCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	botInstance := bot.GetBot()
+func (h *Handler) handleScrapeCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) > 0 {
+		if subCmd, exists := cmd.Subcommand(bot.NormalizeInput(args[0])); exists && subCmd.HandlerFunc != nil {
+			h.runSubcommand(s, m, subCmd, args[1:])
+			return
+		}
+	}
+
+	sites := h.bot.Config.Scrape.Sites
+	target := ""
+	if len(args) > 0 {
+		target = strings.Join(args, " ")
+		site, exists := h.bot.FindScrapeSite(target)
+		if !exists {
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Unknown scrape site '%s'.", target))
+			return
+		}
+		sites = []bot.ScrapeSite{site}
+	}
+
 	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		ctx, cancel := context.WithTimeout(h.bot.Context(), 5*time.Minute)
 		defer cancel()
 
-		botInstance.GetLogger().WithField("user", m.Author.Username).Info("Manual Scraping Initiated")
-		results, err := botInstance.ScrapeGiftCodes(ctx)
+		h.bot.GetLogger().WithField("user", m.Author.Username).Info("Manual Scraping Initiated")
+		results, err := h.bot.RunScrape(ctx, sites, bot.MessageActor(m))
 		if err != nil {
-			bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Scraping failed: %s", err.Error()))
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Scraping failed: %s", err.Error()))
 			return
 		}
 
 		response := formatScrapeResults(results)
-		bot.SendMessage(s, m.ChannelID, response)
+		h.bot.SendLongMessage(s, m.ChannelID, response)
+	}()
+}
+
+// Save the raw responses of the scrape sites as fixtures for offline tests
+func (h *Handler) handleScrapeRecordCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	sites := h.bot.Config.Scrape.Sites
+	target := ""
+	if len(args) > 0 {
+		target = strings.Join(args, " ")
+		site, exists := h.bot.FindScrapeSite(target)
+		if !exists {
+			h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 Unknown scrape site '%s'.", target))
+			return
+		}
+		sites = []bot.ScrapeSite{site}
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(h.bot.Context(), 5*time.Minute)
+		defer cancel()
+
+		results := h.bot.RecordFixtures(ctx, sites, bot.MessageActor(m))
+		response := formatScrapeResults(results)
+		response += fmt.Sprintf("\n✓ Fixtures of the sites without errors were saved to `%s`.", h.bot.Config.Scrape.Fixtures.Dir)
+		h.bot.SendLongMessage(s, m.ChannelID, response)
 	}()
 }
 
@@ -40,11 +85,14 @@ func formatScrapeResults(results []bot.ScrapeResult) string {
 
 	totalCodes := 0
 	for _, result := range results {
-		sb.WriteString(fmt.Sprintf("» %s »\n", result.SiteName))
+		sb.WriteString(fmt.Sprintf("» %s » %s\n", result.SiteName, result.Status))
 		if result.Error != nil {
 			sb.WriteString(fmt.Sprintf("   𐄂 Error: %s\n", result.Error))
 		} else {
 			sb.WriteString(fmt.Sprintf("   Codes Found: %d\n", len(result.Codes)))
+			if result.Rejected > 0 {
+				sb.WriteString(fmt.Sprintf("   Candidates Rejected: %d\n", result.Rejected))
+			}
 			for _, code := range result.Codes {
 				sb.WriteString(fmt.Sprintf("      - %s: %s\n", code.Code, code.Description))
 			}
@@ -57,3 +105,42 @@ func formatScrapeResults(results []bot.ScrapeResult) string {
 
 	return sb.String()
 }
+
+// Show the health of each scrape source
+func (h *Handler) handleScrapeHealthCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	records := h.bot.ListScrapeHealth()
+	if len(records) == 0 {
+		h.bot.SendMessage(s, m.ChannelID, "⚠️ No scrape sites are configured.")
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString("■ Scraper Health ■\n```\n")
+	sb.WriteString(fmt.Sprintf("%-16s %-8s %-16s %5s %5s %8s\n", "Site", "Status", "Last success", "Fails", "Last", "Baseline"))
+	for _, health := range records {
+		status := "ok"
+		switch {
+		case health.Runs == 0:
+			status = "new"
+		case health.Alerting:
+			status = "ALERT"
+		case health.ConsecutiveFailures > 0:
+			status = "failing"
+		}
+		lastSuccess := "never"
+		if !health.LastSuccessAt.IsZero() {
+			lastSuccess = health.LastSuccessAt.UTC().Format("01-02 15:04")
+		}
+		sb.WriteString(fmt.Sprintf("%-16.16s %-8s %-16s %5d %5d %8.1f\n",
+			health.SiteName, status, lastSuccess, health.ConsecutiveFailures, health.LastCodeCount, health.BaselineCodes))
+	}
+	sb.WriteString("```")
+
+	for _, health := range records {
+		if health.ConsecutiveFailures > 0 && health.LastError != "" {
+			sb.WriteString(fmt.Sprintf("\n𐄂 %s: %s", health.SiteName, health.LastError))
+		}
+	}
+
+	h.bot.SendLongMessage(s, m.ChannelID, sb.String())
+}
@@ -0,0 +1,57 @@
+// File: internal/bot/handlers/settings_handlers.go
+
+package handlers
+
+import (
+	"fmt"
+	"the-keeper/internal/bot"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func (h *Handler) registerSettingsHandlers() {
+	h.bot.RegisterHandler("handleSettingsCommand", h.handleSettingsCommand)
+}
+
+// Show or change the per-guild settings
+func (h *Handler) handleSettingsCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if m.GuildID == "" {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Settings can only be changed from within a server.")
+		return
+	}
+
+	settings := h.bot.GetGuildSettings(m.GuildID)
+	if len(args) == 0 {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Server settings:\n  suggestions: %s\n  autoreplies: %s",
+			bot.OnOff(settings.SuggestionsEnabled), bot.OnOff(settings.AutoRepliesEnabled)))
+		return
+	}
+
+	if len(args) != 2 {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("Usage: %s", h.bot.WithPrefix(cmd.Usage)))
+		return
+	}
+
+	enabled, ok := parseOnOff(args[1])
+	if !ok {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 The value must be `on` or `off`.")
+		return
+	}
+
+	name := bot.NormalizeInput(args[0])
+	if err := h.bot.SetGuildSetting(name, enabled, bot.MessageActor(m)); err != nil {
+		h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("✓ %s turned %s.", name, bot.OnOff(enabled)))
+}
+
+func parseOnOff(value string) (bool, bool) {
+	switch bot.NormalizeInput(value) {
+	case "on", "true", "yes", "enable":
+		return true, true
+	case "off", "false", "no", "disable":
+		return false, true
+	}
+	return false, false
+}
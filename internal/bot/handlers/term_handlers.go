@@ -3,62 +3,120 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
 	"fmt"
-	"regexp"
+	"strconv"
 	"strings"
 	"the-keeper/internal/bot"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-var termRegex = regexp.MustCompile(`^\S+$`)
+// termRegex matches term titles, aliases and categories after their spaces
+// are normalized.
+var termRegex = bot.TermNamePattern
 
-func init() {
-	bot.RegisterHandlerLater("handleTermCommand", handleTermCommand)
-	bot.RegisterHandlerLater("handleTermAddCommand", handleTermAddCommand)
-	bot.RegisterHandlerLater("handleTermEditCommand", handleTermEditCommand)
-	bot.RegisterHandlerLater("handleTermRemoveCommand", handleTermRemoveCommand)
-	bot.RegisterHandlerLater("handleTermListCommand", handleTermListCommand)
-	bot.RegisterHandlerLater("handleTermGetCommand", handleTermGetCommand)
+// termSearchLimit caps the results of a term search.
+const termSearchLimit = 25
+
+func (h *Handler) registerTermHandlers() {
+	h.bot.RegisterHandler("handleTermCommand", h.handleTermCommand)
+	h.bot.RegisterHandler("handleTermAddCommand", h.handleTermAddCommand)
+	h.bot.RegisterHandler("handleTermEditCommand", h.handleTermEditCommand)
+	h.bot.RegisterHandler("handleTermRemoveCommand", h.handleTermRemoveCommand)
+	h.bot.RegisterHandler("handleTermListCommand", h.handleTermListCommand)
+	h.bot.RegisterHandler("handleTermGetCommand", h.handleTermGetCommand)
+	h.bot.RegisterHandler("handleTermAliasCommand", h.handleTermAliasCommand)
+	h.bot.RegisterHandler("handleTermUnaliasCommand", h.handleTermUnaliasCommand)
+	h.bot.RegisterHandler("handleTermCategoryCommand", h.handleTermCategoryCommand)
+	h.bot.RegisterHandler("handleTermSearchCommand", h.handleTermSearchCommand)
+	h.bot.RegisterHandler("handleTermHistoryCommand", h.handleTermHistoryCommand)
+	h.bot.RegisterHandler("handleTermDiffCommand", h.handleTermDiffCommand)
+	h.bot.RegisterHandler("handleTermRevertCommand", h.handleTermRevertCommand)
+	h.bot.RegisterHandler("handleTermRestoreCommand", h.handleTermRestoreCommand)
+	h.bot.RegisterHandler("handleTermSuggestionsCommand", h.handleTermSuggestionsCommand)
+	h.bot.RegisterHandler("handleTermTriggerCommand", h.handleTermTriggerCommand)
+	h.bot.RegisterHandler("handleTermUntriggerCommand", h.handleTermUntriggerCommand)
+	h.bot.RegisterHandler("handleTermExportCommand", h.handleTermExportCommand)
+	h.bot.RegisterHandler("handleTermImportCommand", h.handleTermImportCommand)
+	h.bot.RegisterComponentHandler(bot.GlossaryImportComponent, h.handleGlossaryImportComponent)
+	h.bot.RegisterComponentHandler(bot.TermSuggestionComponent, h.handleTermSuggestionComponent)
 }
 
 // Main term command handler
-func handleTermCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) handleTermCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) == 0 {
-		s.ChannelMessageSend(m.ChannelID, "Usage: term <add|edit|remove|list|get> [args]")
+		s.ChannelMessageSend(m.ChannelID, "Usage: term <add|edit|remove|list|search|alias|unalias|category|history|diff|revert|restore|suggestions|trigger|untrigger|export|import> [args]")
 		return
 	}
 
-	subcommand := bot.NormalizeInput(args[0])
-	switch subcommand {
-	case "add":
-		handleTermAddCommand(s, m, args[1:], cmd)
-	case "edit":
-		handleTermEditCommand(s, m, args[1:], cmd)
-	case "remove":
-		handleTermRemoveCommand(s, m, args[1:], cmd)
-	case "list":
-		handleTermListCommand(s, m, args[1:], cmd)
-	default:
-		handleTermGetCommand(s, m, args, cmd)
+	if subCmd, exists := cmd.Subcommand(bot.NormalizeInput(args[0])); exists && subCmd.HandlerFunc != nil {
+		h.runSubcommand(s, m, subCmd, args[1:])
+		return
 	}
+	h.handleTermGetCommand(s, m, args, cmd)
 }
 
-// Add a new term
-func handleTermAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+// splitTermTitle splits a leading title off args. Multi-word titles are
+// wrapped in double quotes, e.g. "bear trap"; otherwise the title is the
+// first argument.
+func splitTermTitle(args []string) (string, []string, bool) {
+	if len(args) == 0 {
+		return "", nil, false
+	}
+	if !strings.HasPrefix(args[0], `"`) && !strings.HasPrefix(args[0], "“") {
+		return args[0], args[1:], true
+	}
+	for i, arg := range args {
+		trimmed := strings.TrimLeft(arg, `"“`)
+		if i == 0 && trimmed == "" {
+			continue
+		}
+		if strings.HasSuffix(trimmed, `"`) || strings.HasSuffix(trimmed, "”") {
+			title := strings.Trim(strings.Join(args[:i+1], " "), `"“”`)
+			return bot.NormalizeTermTitle(title), args[i+1:], true
+		}
+	}
+	return "", nil, false
+}
+
+// splitTermRevision splits args into a title and a trailing revision number.
+func splitTermRevision(args []string) (string, int, bool) {
 	if len(args) < 2 {
-		s.ChannelMessageSend(m.ChannelID, "Usage: term add <term> <description>")
-		return
+		return "", 0, false
 	}
+	revision, err := strconv.Atoi(strings.TrimPrefix(args[len(args)-1], "#"))
+	if err != nil || revision < 1 {
+		return "", 0, false
+	}
+	return termTitleArg(args[:len(args)-1]), revision, true
+}
 
-	term := args[0]
+// termTitleArg returns the title given as all of args, with or without quotes.
+func termTitleArg(args []string) string {
+	return bot.NormalizeTermTitle(strings.Trim(strings.Join(args, " "), `"“”`))
+}
+
+// Add a new term
+func (h *Handler) handleTermAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, rest, ok := splitTermTitle(args)
+	if !ok || len(rest) == 0 {
+		s.ChannelMessageSend(m.ChannelID, `Usage: term add <term|"multi-word term"> <description>`)
+		return
+	}
 	if !termRegex.MatchString(term) {
-		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid term. The term must be a single word without spaces.")
+		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid term. The term must be at most 64 characters without quotes.")
+		return
+	}
+	description := strings.Join(rest, " ")
+	if !h.bot.IsGlossaryEditor(s, m.GuildID, m.Author.ID) {
+		h.suggestTermChange(s, m, bot.TermActionAdd, term, description)
 		return
 	}
-	description := strings.Join(args[1:], " ")
 
-	err := bot.GetBot().AddTerm(term, description)
+	err := h.bot.AddTerm(term, description, bot.MessageActor(m))
 	if err != nil {
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to add term: %v", err))
 		return
@@ -68,88 +126,726 @@ func handleTermAddCommand(s *discordgo.Session, m *discordgo.MessageCreate, args
 }
 
 // Edit an existing term
-func handleTermEditCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	if len(args) < 2 {
-		s.ChannelMessageSend(m.ChannelID, "Usage: term edit <term> <new description>")
+func (h *Handler) handleTermEditCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, rest, ok := splitTermTitle(args)
+	if !ok || len(rest) == 0 {
+		s.ChannelMessageSend(m.ChannelID, `Usage: term edit <term|"multi-word term"> <new description>`)
 		return
 	}
+	newDescription := strings.Join(rest, " ")
 
-	term := args[0]
-	if !termRegex.MatchString(term) {
-		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid term. The term must be a single word without spaces.")
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to edit term: %v", err))
+		return
+	}
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.suggestTermChange(s, m, bot.TermActionEdit, t.Term, newDescription)
 		return
 	}
-	newDescription := strings.Join(args[1:], " ")
 
-	err := bot.GetBot().EditTerm(term, newDescription)
+	err = h.bot.EditTerm(t.Term, newDescription, bot.MessageActor(m))
 	if err != nil {
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to edit term: %v", err))
 		return
 	}
 
-	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' updated successfully!", term))
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' updated successfully!", t.Term))
 }
 
 // Delete an existing term
-func handleTermRemoveCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+func (h *Handler) handleTermRemoveCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) < 1 {
 		s.ChannelMessageSend(m.ChannelID, "Usage: term remove <term>")
 		return
 	}
 
-	term := args[0]
-	if !termRegex.MatchString(term) {
-		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid term. The term must be a single word without spaces.")
+	t, err := h.bot.FindTerm(termTitleArg(args))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to remove term: %v", err))
 		return
 	}
-	err := bot.GetBot().RemoveTerm(term)
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.suggestTermChange(s, m, bot.TermActionRemove, t.Term, "")
+		return
+	}
+
+	err = h.bot.RemoveTerm(t.Term, bot.MessageActor(m))
 	if err != nil {
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to remove term: %v", err))
 		return
 	}
 
-	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' removed successfully!", term))
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' removed successfully!", t.Term))
 }
 
-// List all terms
-func handleTermListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
-	terms, err := bot.GetBot().ListTerms()
+// List all terms grouped by category, or the terms of one category
+func (h *Handler) handleTermListCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	category := termTitleArg(args)
+	terms, err := h.bot.ListTerms(category)
 	if err != nil {
 		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to list terms: %v", err))
 		return
 	}
 
 	if len(terms) == 0 {
+		if category != "" {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ No terms in category '%s'.", category))
+			return
+		}
 		s.ChannelMessageSend(m.ChannelID, "⚠️ No terms available.")
 		return
 	}
 
-	var response strings.Builder
-	response.WriteString("Terms:\n")
-	for _, term := range terms {
-		response.WriteString(fmt.Sprintf("- %s\n", term.Term))
+	lines := make([]string, 0, len(terms))
+	group := ""
+	for i, term := range terms {
+		if i == 0 || term.DisplayCategory() != group {
+			group = term.DisplayCategory()
+			lines = append(lines, fmt.Sprintf("**%s**", group))
+		}
+		line := fmt.Sprintf("- %s", term.Term)
+		if aliases := term.AliasList(); len(aliases) > 0 {
+			line += fmt.Sprintf(" (%s)", strings.Join(aliases, ", "))
+		}
+		lines = append(lines, line)
 	}
 
-	s.ChannelMessageSend(m.ChannelID, response.String())
+	title := "Terms"
+	if category != "" {
+		title = fmt.Sprintf("Terms in %s", terms[0].DisplayCategory())
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, title, lines, 20, 1)
 }
 
-// Get a term's description
-func handleTermGetCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+// Search term titles, aliases and descriptions: term search <words>
+func (h *Handler) handleTermSearchCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
 	if len(args) < 1 {
-		s.ChannelMessageSend(m.ChannelID, "Usage: term get <term>")
+		s.ChannelMessageSend(m.ChannelID, "Usage: term search <words>")
 		return
 	}
 
-	term := args[0]
-	if !termRegex.MatchString(term) {
-		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid term. The term must be a single word without spaces.")
+	query := strings.Join(args, " ")
+	results, err := h.bot.SearchTerms(query, termSearchLimit)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to search terms: %v", err))
+		return
+	}
+	if len(results) == 0 {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ No terms match '%s'.", query))
+		return
+	}
+
+	lines := make([]string, 0, len(results))
+	for _, result := range results {
+		lines = append(lines, fmt.Sprintf("%s — %s", result.Term, result.Snippet))
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, fmt.Sprintf("🔍 Terms matching '%s'", query), lines, 5, 1)
+}
+
+// Show the revisions of a term: term history <term>
+func (h *Handler) handleTermHistoryCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term history <term>")
+		return
+	}
+
+	t, revisions, err := h.bot.TermHistory(termTitleArg(args))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to load history: %v", err))
+		return
+	}
+	if len(revisions) == 0 {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Term '%s' has no revisions.", t.Term))
+		return
+	}
+
+	lines := make([]string, 0, len(revisions))
+	for _, rev := range revisions {
+		author := "unknown"
+		if rev.AuthorID != "" {
+			author = fmt.Sprintf("<@%s>", rev.AuthorID)
+		}
+		line := fmt.Sprintf("`#%d` %s by %s, %s", rev.Revision, rev.Action, author, rev.CreatedAt.UTC().Format("2006-01-02 15:04"))
+		if rev.Note != "" {
+			line += fmt.Sprintf(" (%s)", rev.Note)
+		}
+		lines = append(lines, line)
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, fmt.Sprintf("📜 History of %s", t.Term), lines, 10, 1)
+}
+
+// Show what a revision changed: term diff <term> <revision>
+func (h *Handler) handleTermDiffCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, revision, ok := splitTermRevision(args)
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term diff <term> <revision>")
+		return
+	}
+
+	current, previous, err := h.bot.TermRevisionDiff(term, revision)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to load revision: %v", err))
+		return
+	}
+
+	diff := bot.DiffLines(previous.Text(), current.Text())
+	h.bot.SendLongMessage(s, m.ChannelID, fmt.Sprintf("Changes in revision #%d of '%s' (%s):\n```diff\n%s\n```",
+		revision, current.Term, current.Action, strings.Join(diff, "\n")))
+}
+
+// Roll a term back to an earlier revision: term revert <term> <revision>
+func (h *Handler) handleTermRevertCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, revision, ok := splitTermRevision(args)
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term revert <term> <revision>")
+		return
+	}
+
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to revert term: %v", err))
+		return
+	}
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+
+	rev, skipped, err := h.bot.RevertTerm(t.Term, revision, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to revert term: %v", err))
+		return
+	}
+
+	response := fmt.Sprintf("✓ Term '%s' reverted to revision #%d.", rev.Term, revision)
+	if len(skipped) > 0 {
+		response += fmt.Sprintf("\n⚠️ Aliases now used by other terms were not restored: %s", strings.Join(skipped, ", "))
+	}
+	s.ChannelMessageSend(m.ChannelID, response)
+}
+
+// Bring back a removed term: term restore <term>
+func (h *Handler) handleTermRestoreCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term restore <term>")
+		return
+	}
+	if !h.bot.IsGlossaryEditor(s, m.GuildID, m.Author.ID) {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Only glossary editors can restore removed terms.")
+		return
+	}
+
+	t, skipped, err := h.bot.RestoreTerm(termTitleArg(args), bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to restore term: %v", err))
+		return
+	}
+
+	response := fmt.Sprintf("✓ Term '%s' restored successfully!", t.Term)
+	if len(skipped) > 0 {
+		response += fmt.Sprintf("\n⚠️ Aliases now used by other terms were not restored: %s", strings.Join(skipped, ", "))
+	}
+	s.ChannelMessageSend(m.ChannelID, response)
+}
+
+// suggestTermChange queues a change by a member without edit rights for
+// review by the glossary editors.
+func (h *Handler) suggestTermChange(s *discordgo.Session, m *discordgo.MessageCreate, action, term, description string) {
+	suggestion, err := h.bot.SuggestTermChange(action, term, description, m.ChannelID, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to suggest change: %v", err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("📝 Your suggested %s was sent to the glossary editors for review (suggestion #%d).",
+		suggestion.Summary(), suggestion.ID))
+}
+
+func (h *Handler) denyTermChange(s *discordgo.Session, m *discordgo.MessageCreate, term string) {
+	h.bot.SendMessage(s, m.ChannelID, fmt.Sprintf("𐄂 You do not have permission to change term '%s'.", term))
+}
+
+// List suggested term changes, or review one: term suggestions [accept|reject <id>]
+func (h *Handler) handleTermSuggestionsCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) == 0 {
+		suggestions, err := h.bot.ListTermSuggestions()
+		if err != nil {
+			h.bot.GetLogger().WithError(err).Error("Error retrieving term suggestions")
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to list term suggestions: %v", err))
+			return
+		}
+		if len(suggestions) == 0 {
+			s.ChannelMessageSend(m.ChannelID, "✓ No term changes are waiting for review.")
+			return
+		}
+
+		lines := make([]string, 0, len(suggestions))
+		for _, suggestion := range suggestions {
+			lines = append(lines, fmt.Sprintf("`#%d` %s by <@%s>, %s", suggestion.ID, suggestion.Summary(),
+				suggestion.SuggestedBy, suggestion.CreatedAt.UTC().Format("2006-01-02 15:04")))
+		}
+		h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, "📝 Term changes awaiting review", lines, 10, 1)
+		return
+	}
+
+	action := bot.NormalizeInput(args[0])
+	var id uint64
+	var err error
+	if len(args) == 2 {
+		id, err = strconv.ParseUint(strings.TrimPrefix(args[1], "#"), 10, 64)
+	}
+	if len(args) != 2 || err != nil || (action != bot.TermSuggestionAccept && action != bot.TermSuggestionReject) {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term suggestions [accept|reject <id>]")
+		return
+	}
+
+	suggestion, err := h.bot.GetTermSuggestion(uint(id))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ %v", err))
+		return
+	}
+	if !h.bot.CanReviewTermSuggestion(s, m.GuildID, m.Author.ID, suggestion) {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Only glossary editors can review term suggestions.")
+		return
+	}
+
+	suggestion, err = h.bot.ReviewTermSuggestion(suggestion.ID, action == bot.TermSuggestionAccept, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	if suggestion.Status == bot.TermSuggestionStale {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Suggestion #%d (%s) is stale: the term changed since it was suggested, so it was not applied.",
+			suggestion.ID, suggestion.Summary()))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Suggestion #%d (%s) %s.", suggestion.ID, suggestion.Summary(), suggestion.Status))
+}
+
+// Handle the accept and reject buttons of a suggested term change
+func (h *Handler) handleTermSuggestionComponent(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 2 {
+		return
+	}
+	id, err := strconv.ParseUint(args[1], 10, 64)
+	if err != nil {
+		return
+	}
+	userID := bot.InteractionUserID(i)
+
+	suggestion, err := h.bot.GetTermSuggestion(uint(id))
+	if err != nil {
+		h.bot.RespondEphemeral(s, i, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	if !h.bot.CanReviewTermSuggestion(s, i.GuildID, userID, suggestion) {
+		h.bot.RespondEphemeral(s, i, "𐄂 Only glossary editors can review term suggestions.")
+		return
+	}
+
+	suggestion, err = h.bot.ReviewTermSuggestion(suggestion.ID, args[0] == bot.TermSuggestionAccept, bot.InteractionActor(i))
+	if err != nil {
+		h.bot.RespondEphemeral(s, i, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+
+	outcome := fmt.Sprintf("𐄂 Rejected by <@%s>", userID)
+	switch suggestion.Status {
+	case bot.TermSuggestionAccepted:
+		outcome = fmt.Sprintf("✓ Accepted by <@%s>", userID)
+	case bot.TermSuggestionStale:
+		outcome = fmt.Sprintf("⚠️ Not applied: the term changed since this was suggested (accepted by <@%s>)", userID)
+	}
+	embed := suggestion.ReviewEmbed()
+	embed.Title = fmt.Sprintf("Term suggestion #%d reviewed", suggestion.ID)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    outcome,
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to update term suggestion review message")
+	}
+}
+
+// Add an alias to a term: term alias <term|"multi-word term"> <alias>
+func (h *Handler) handleTermAliasCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, rest, ok := splitTermTitle(args)
+	if !ok || len(rest) == 0 {
+		s.ChannelMessageSend(m.ChannelID, `Usage: term alias <term|"multi-word term"> <alias>`)
+		return
+	}
+	alias := termTitleArg(rest)
+	if !termRegex.MatchString(alias) {
+		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid alias. The alias must be at most 64 characters without quotes.")
+		return
+	}
+
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to add alias: %v", err))
+		return
+	}
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+
+	t, err = h.bot.AddTermAlias(t.Term, alias, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to add alias: %v", err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ '%s' is now an alias of '%s'.", alias, t.Term))
+}
+
+// Remove an alias: term unalias <alias>
+func (h *Handler) handleTermUnaliasCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term unalias <alias>")
+		return
+	}
+
+	alias := termTitleArg(args)
+	if t, err := h.bot.FindTerm(alias); err == nil && !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+
+	t, err := h.bot.RemoveTermAlias(alias, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to remove alias: %v", err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Alias '%s' removed from '%s'.", alias, t.Term))
+}
+
+// Show or add the keywords that make the bot explain a term in auto-reply
+// channels: term trigger <term|"multi-word term"> [keyword]
+func (h *Handler) handleTermTriggerCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, rest, ok := splitTermTitle(args)
+	if !ok {
+		s.ChannelMessageSend(m.ChannelID, `Usage: term trigger <term|"multi-word term"> [keyword]`)
+		return
+	}
+
+	if len(rest) == 0 {
+		t, keywords, err := h.bot.TermTriggers(term)
+		if err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to load triggers: %v", err))
+			return
+		}
+		if len(keywords) == 0 {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Term '%s' has no trigger keywords.", t.Term))
+			return
+		}
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("Term '%s' is triggered by: %s", t.Term, strings.Join(keywords, ", ")))
+		return
+	}
+
+	keyword := termTitleArg(rest)
+	if !termRegex.MatchString(keyword) || len([]rune(keyword)) < 2 {
+		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid keyword. The keyword must be 2 to 64 characters without quotes.")
+		return
+	}
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to add trigger: %v", err))
+		return
+	}
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+
+	t, err = h.bot.AddTermTrigger(t.Term, keyword, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to add trigger: %v", err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ '%s' now triggers term '%s'.", keyword, t.Term))
+}
+
+// Remove a trigger keyword: term untrigger <keyword>
+func (h *Handler) handleTermUntriggerCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term untrigger <keyword>")
+		return
+	}
+
+	keyword := termTitleArg(args)
+	if _, t, err := h.bot.FindTermTrigger(keyword); err == nil && !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+
+	t, err := h.bot.RemoveTermTrigger(keyword, bot.MessageActor(m))
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to remove trigger: %v", err))
+		return
+	}
+
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ '%s' no longer triggers term '%s'.", keyword, t.Term))
+}
+
+// Send the glossary as a file: term export [yaml|csv|md]
+func (h *Handler) handleTermExportCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	format := bot.GlossaryYAML
+	if len(args) > 0 {
+		var err error
+		if format, err = bot.ParseGlossaryFormat(args[0]); err != nil {
+			s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("𐄂 %v", err))
+			return
+		}
+	}
+
+	entries, err := h.bot.GlossaryEntries()
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to export terms: %v", err))
+		return
+	}
+	data, err := bot.EncodeGlossary(entries, format)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to export terms: %v", err))
+		return
+	}
+
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("📤 Glossary export (%d terms)", len(entries)),
+		Files: []*discordgo.File{{
+			Name:   "glossary." + format,
+			Reader: bytes.NewReader(data),
+		}},
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Error sending glossary export")
+	}
+}
+
+// Import terms from an attached file: term import [skip|overwrite|rename] [dry-run]
+func (h *Handler) handleTermImportCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if !h.bot.IsGlossaryEditor(s, m.GuildID, m.Author.ID) {
+		h.bot.SendMessage(s, m.ChannelID, "𐄂 Only glossary editors can import terms.")
+		return
+	}
+
+	conflict, dryRun := bot.ImportSkip, false
+	for _, arg := range args {
+		if arg = bot.NormalizeInput(arg); arg == "dry-run" || arg == "dryrun" {
+			dryRun = true
+			continue
+		}
+		var err error
+		if conflict, err = bot.ParseImportConflict(arg); err != nil {
+			s.ChannelMessageSend(m.ChannelID, "Usage: term import [skip|overwrite|rename] [dry-run], with the glossary file attached")
+			return
+		}
+	}
+
+	if len(m.Attachments) != 1 {
+		s.ChannelMessageSend(m.ChannelID, "𐄂 Attach one glossary file (.yaml, .csv or .md) to the command.")
 		return
 	}
-	description, err := bot.GetBot().GetTermDescription(term)
+	attachment := m.Attachments[0]
+	format, err := bot.GlossaryFormatOf(attachment.Filename)
 	if err != nil {
-		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to get term description: %v", err))
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("𐄂 %v", err))
 		return
 	}
+	if attachment.Size > bot.MaxGlossaryFileSize {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("𐄂 The file is larger than %d KB.", bot.MaxGlossaryFileSize/1024))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(h.bot.Context(), 30*time.Second)
+	defer cancel()
+	data, err := h.bot.FetchGlossaryFile(ctx, attachment.URL)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to read the file: %v", err))
+		return
+	}
+	entries, err := bot.DecodeGlossary(data, format)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to read the file: %v", err))
+		return
+	}
+	if len(entries) == 0 {
+		s.ChannelMessageSend(m.ChannelID, "⚠️ The file has no terms.")
+		return
+	}
+
+	plan, err := h.bot.PlanGlossaryImport(entries, conflict)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to plan import: %v", err))
+		return
+	}
+	title := fmt.Sprintf("📥 Importing %s: %s", attachment.Filename, plan.Summary())
+	if dryRun {
+		title = fmt.Sprintf("📥 Dry run of %s: %s", attachment.Filename, plan.Summary())
+	}
+	h.bot.SendPaginated(s, m.ChannelID, m.Author.ID, bot.Truncate(title, 256), plan.Lines(), 15, 1)
+	if dryRun {
+		s.ChannelMessageSend(m.ChannelID, "✓ Dry run finished, nothing was changed.")
+		return
+	}
+	if plan.Count(bot.ImportSkip) == len(plan.Items) {
+		s.ChannelMessageSend(m.ChannelID, "⚠️ Every entry would be skipped, so there is nothing to import.")
+		return
+	}
+
+	id := h.bot.StageGlossaryImport(m.Author.ID, attachment.Filename, entries, conflict)
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("Import %s with conflicts set to %s? (%s)", attachment.Filename, conflict, plan.Summary()),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Import",
+					Style:    discordgo.SuccessButton,
+					CustomID: bot.ComponentID(bot.GlossaryImportComponent, bot.GlossaryImportConfirm, id),
+				},
+				discordgo.Button{
+					Label:    "Cancel",
+					Style:    discordgo.SecondaryButton,
+					CustomID: bot.ComponentID(bot.GlossaryImportComponent, bot.GlossaryImportCancel, id),
+				},
+			}},
+		},
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Error sending glossary import confirmation")
+	}
+}
+
+// Handle the import and cancel buttons of a glossary import preview
+func (h *Handler) handleGlossaryImportComponent(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) < 2 {
+		return
+	}
+	action, id := args[0], args[1]
+	userID := bot.InteractionUserID(i)
+
+	if action == bot.GlossaryImportCancel {
+		if err := h.bot.CancelGlossaryImport(id, userID); err != nil {
+			h.bot.RespondEphemeral(s, i, fmt.Sprintf("𐄂 %v", err))
+			return
+		}
+		h.updateGlossaryImportMessage(s, i, "Import cancelled.")
+		return
+	}
+
+	if err := h.bot.CheckGlossaryImport(id, userID); err != nil {
+		h.bot.RespondEphemeral(s, i, fmt.Sprintf("𐄂 %v", err))
+		return
+	}
+	// Large imports can take longer than Discord waits for an answer
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to defer glossary import")
+		return
+	}
+
+	filename, plan, skipped, err := h.bot.ConfirmGlossaryImport(id, bot.InteractionActor(i))
+	content := fmt.Sprintf("✓ Imported %s: %s.", filename, plan.Summary())
+	if err != nil {
+		content = fmt.Sprintf("𐄂 Failed to import %s, nothing was changed: %v", filename, err)
+	} else if len(skipped) > 0 {
+		content += fmt.Sprintf("\n⚠️ Aliases and triggers that are invalid or already used were left out: %s",
+			strings.Join(skipped, ", "))
+	}
+
+	content = bot.Truncate(content, bot.MessageLimit)
+	components := []discordgo.MessageComponent{}
+	_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content, Components: &components})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to update glossary import message")
+	}
+}
+
+func (h *Handler) updateGlossaryImportMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
+	if err != nil {
+		h.bot.GetLogger().WithError(err).Error("Failed to update glossary import message")
+	}
+}
+
+// Move a term into a category: term category <term|"multi-word term"> <category|none>
+func (h *Handler) handleTermCategoryCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	term, rest, ok := splitTermTitle(args)
+	if !ok || len(rest) == 0 {
+		s.ChannelMessageSend(m.ChannelID, `Usage: term category <term|"multi-word term"> <category|none>`)
+		return
+	}
+	category := termTitleArg(rest)
+	if strings.EqualFold(category, "none") {
+		category = ""
+	} else if !termRegex.MatchString(category) || strings.EqualFold(category, bot.UncategorizedTerms) {
+		s.ChannelMessageSend(m.ChannelID, "𐄂 Invalid category. The category must be at most 64 characters without quotes.")
+		return
+	}
+
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to set category: %v", err))
+		return
+	}
+	if !h.bot.CanEditTerm(s, m.GuildID, m.Author.ID, t) {
+		h.denyTermChange(s, m, t.Term)
+		return
+	}
+	if err := h.bot.SetTermCategory(t.Term, category, bot.MessageActor(m)); err != nil {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("⚠️ Failed to set category: %v", err))
+		return
+	}
+
+	if category == "" {
+		s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' is now uncategorized.", t.Term))
+		return
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("✓ Term '%s' moved to category '%s'.", t.Term, category))
+}
 
-	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("```%s```", description))
+// Get a term's description
+func (h *Handler) handleTermGetCommand(s *discordgo.Session, m *discordgo.MessageCreate, args []string, cmd *bot.Command) {
+	if len(args) < 1 {
+		s.ChannelMessageSend(m.ChannelID, "Usage: term <term>")
+		return
+	}
+
+	term := termTitleArg(args)
+	t, err := h.bot.FindTerm(term)
+	if err != nil {
+		response := fmt.Sprintf("⚠️ Failed to get term description: %v", err)
+		if h.bot.SuggestionsEnabled(m.GuildID) {
+			if suggestion := bot.FormatSuggestions("", h.bot.SuggestTerms(term)); suggestion != "" {
+				response += "\n" + suggestion
+			}
+		}
+		s.ChannelMessageSend(m.ChannelID, response)
+		return
+	}
+
+	header := fmt.Sprintf("**%s**", t.Term)
+	if t.Category != "" {
+		header += fmt.Sprintf(" · %s", t.Category)
+	}
+	if aliases := t.AliasList(); len(aliases) > 0 {
+		header += fmt.Sprintf("\nAlso known as: %s", strings.Join(aliases, ", "))
+	}
+	s.ChannelMessageSend(m.ChannelID, fmt.Sprintf("%s\n```%s```", header, t.Description))
 }
@@ -2,13 +2,19 @@
 package bot
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-func runMigrations(db *gorm.DB) error {
+// termTitleIndex keeps term titles unique regardless of case among the terms
+// that haven't been removed, so a removed term doesn't block its title.
+const termTitleIndex = `CREATE UNIQUE INDEX IF NOT EXISTS idx_terms_term_active ON terms(term COLLATE NOCASE) WHERE deleted_at IS NULL`
+
+func runMigrations(db *gorm.DB, logger *logrus.Logger) error {
 	m := gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{
 		{
 			ID: "202310071200", // Static ID for creating the terms table
@@ -57,18 +63,356 @@ func runMigrations(db *gorm.DB) error {
 				return tx.Migrator().DropTable("gift_code_redemptions")
 			},
 		},
+		{
+			ID: "202610190100", // Static ID for creating the guild_settings table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the guild_settings table
+				type GuildSettings struct {
+					GuildID            string `gorm:"primaryKey"`
+					SuggestionsEnabled bool
+					UpdatedAt          time.Time
+				}
+				return tx.AutoMigrate(&GuildSettings{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("guild_settings")
+			},
+		},
+		{
+			ID: "202610190200", // Static ID for creating the audit_events table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the audit_events table
+				type AuditEvent struct {
+					ID        uint   `gorm:"primaryKey"`
+					ActorID   string `gorm:"index"`
+					ActorName string
+					GuildID   string `gorm:"index"`
+					Action    string `gorm:"index"`
+					Target    string
+					Before    string
+					After     string
+					CreatedAt time.Time `gorm:"index"`
+				}
+				return tx.AutoMigrate(&AuditEvent{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("audit_events")
+			},
+		},
+		{
+			ID: "202610190300", // Static ID for creating the scrape_site_states table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the scrape_site_states table
+				type ScrapeSiteState struct {
+					SiteName     string `gorm:"primaryKey"`
+					ConfigHash   string
+					ETag         string
+					LastModified string
+					ContentHash  string
+					Codes        string
+					Rejected     int
+					CheckedAt    time.Time
+					ChangedAt    time.Time
+				}
+				return tx.AutoMigrate(&ScrapeSiteState{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("scrape_site_states")
+			},
+		},
+		{
+			ID: "202610190400", // Static ID for creating the scrape_site_healths table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the scrape_site_healths table
+				type ScrapeSiteHealth struct {
+					SiteName            string `gorm:"primaryKey"`
+					Runs                int
+					SuccessfulRuns      int
+					ConsecutiveFailures int
+					LastSuccessAt       time.Time
+					LastErrorAt         time.Time
+					LastError           string
+					LastCodeCount       int
+					BaselineCodes       float64
+					Alerting            bool
+				}
+				return tx.AutoMigrate(&ScrapeSiteHealth{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("scrape_site_healths")
+			},
+		},
+		{
+			ID: "202610190500", // Static ID for creating the discovered_codes table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the discovered_codes table
+				type DiscoveredCode struct {
+					Code              string `gorm:"primaryKey"`
+					Description       string
+					Sources           string
+					Confidence        float64
+					Validation        string
+					ValidationMessage string
+					Status            string `gorm:"index"`
+					ReviewedBy        string
+					FirstSeenAt       time.Time
+					UpdatedAt         time.Time
+				}
+				return tx.AutoMigrate(&DiscoveredCode{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("discovered_codes")
+			},
+		},
+		{
+			ID: "202610190600", // Static ID for adding submitter columns to the discovered_codes table
+			Migrate: func(tx *gorm.DB) error {
+				// Add the submitter columns to the discovered_codes table
+				type DiscoveredCode struct {
+					Code              string `gorm:"primaryKey"`
+					Description       string
+					Sources           string
+					Confidence        float64
+					Validation        string
+					ValidationMessage string
+					Status            string `gorm:"index"`
+					ReviewedBy        string
+					SubmittedBy       string
+					SubmitChannelID   string
+					FirstSeenAt       time.Time
+					UpdatedAt         time.Time
+				}
+				return tx.AutoMigrate(&DiscoveredCode{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn("discovered_codes", "submitted_by"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn("discovered_codes", "submit_channel_id")
+			},
+		},
+		{
+			ID: "202610190700", // Static ID for creating the notification_routes table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the notification_routes table
+				type NotificationRoute struct {
+					ID        uint `gorm:"primaryKey"`
+					Name      string
+					Event     string `gorm:"index"`
+					Target    string
+					ChannelID string
+					RoleID    string
+					UserIDs   string
+					URL       string
+					Template  string
+					CreatedBy string
+					CreatedAt time.Time
+				}
+				return tx.AutoMigrate(&NotificationRoute{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("notification_routes")
+			},
+		},
+		{
+			ID: "202610190800", // Static ID for creating the user_notification_settings table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the user_notification_settings table
+				type UserNotificationSettings struct {
+					DiscordID     string `gorm:"primaryKey"`
+					DMEnabled     bool   `gorm:"index"`
+					DMFailures    int
+					DMPaused      bool
+					LastDMError   string
+					LastDMErrorAt time.Time
+					UpdatedAt     time.Time
+				}
+				return tx.AutoMigrate(&UserNotificationSettings{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("user_notification_settings")
+			},
+		},
+		{
+			ID: "202610190900", // Static ID for adding category and author columns to the terms table and creating the term_aliases table
+			Migrate: func(tx *gorm.DB) error {
+				// Add the category and author columns; existing terms stay uncategorized
+				type Term struct {
+					gorm.Model
+					Term        string `gorm:"uniqueIndex;not null"`
+					Description string `gorm:"not null"`
+					Category    string `gorm:"index"`
+					AuthorID    string
+				}
+				// Create the term_aliases table
+				type TermAlias struct {
+					ID     uint   `gorm:"primaryKey"`
+					TermID uint   `gorm:"index;not null"`
+					Alias  string `gorm:"uniqueIndex;not null"`
+				}
+				if err := tx.AutoMigrate(&Term{}, &TermAlias{}); err != nil {
+					return err
+				}
+				return tx.Exec("UPDATE terms SET category = '', author_id = '' WHERE category IS NULL").Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable("term_aliases"); err != nil {
+					return err
+				}
+				if err := tx.Migrator().DropColumn("terms", "category"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn("terms", "author_id")
+			},
+		},
+		{
+			ID: "202610191000", // Static ID for creating the term_revisions table
+			Migrate: func(tx *gorm.DB) error {
+				// Create the term_revisions table
+				type TermRevision struct {
+					ID          uint `gorm:"primaryKey"`
+					TermID      uint `gorm:"uniqueIndex:idx_term_revision;not null"`
+					Revision    int  `gorm:"uniqueIndex:idx_term_revision;not null"`
+					Action      string
+					Note        string
+					Term        string
+					Description string
+					Category    string
+					Aliases     string
+					AuthorID    string
+					CreatedAt   time.Time
+				}
+				if err := tx.AutoMigrate(&TermRevision{}); err != nil {
+					return err
+				}
+
+				// Start the history of existing terms with their current state
+				type Term struct {
+					gorm.Model
+					Term        string
+					Description string
+					Category    string
+					AuthorID    string
+				}
+				var terms []Term
+				if err := tx.Unscoped().Find(&terms).Error; err != nil {
+					return err
+				}
+				for _, t := range terms {
+					var aliases []string
+					if err := tx.Table("term_aliases").Where("term_id = ?", t.ID).Pluck("alias", &aliases).Error; err != nil {
+						return err
+					}
+					encoded, err := json.Marshal(aliases)
+					if err != nil {
+						return err
+					}
+					revisions := []TermRevision{{
+						TermID: t.ID, Revision: 1, Action: "add", Note: "imported",
+						Term: t.Term, Description: t.Description, Category: t.Category, Aliases: string(encoded),
+						AuthorID: t.AuthorID, CreatedAt: t.CreatedAt,
+					}}
+					if t.DeletedAt.Valid {
+						removed := revisions[0]
+						removed.Revision, removed.Action, removed.Note, removed.AuthorID, removed.CreatedAt = 2, "remove", "", "", t.DeletedAt.Time
+						revisions = append(revisions, removed)
+					}
+					if err := tx.Create(&revisions).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("term_revisions")
+			},
+		},
+		{
+			ID: "202610191100", // Static ID for creating the term_suggestions table
+			Migrate: func(tx *gorm.DB) error {
+				type TermSuggestion struct {
+					ID          uint `gorm:"primaryKey"`
+					Action      string
+					Term        string
+					Description string
+					Previous    string
+					SuggestedBy string
+					ChannelID   string
+					Status      string `gorm:"index"`
+					ReviewedBy  string
+					CreatedAt   time.Time
+					UpdatedAt   time.Time
+				}
+				return tx.AutoMigrate(&TermSuggestion{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("term_suggestions")
+			},
+		},
+		{
+			ID: "202610191200", // Static ID for creating the term_triggers table
+			Migrate: func(tx *gorm.DB) error {
+				type TermTrigger struct {
+					ID      uint   `gorm:"primaryKey"`
+					TermID  uint   `gorm:"index;not null"`
+					Keyword string `gorm:"uniqueIndex;not null"`
+				}
+				return tx.AutoMigrate(&TermTrigger{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable("term_triggers")
+			},
+		},
+		{
+			ID: "202610191210", // Static ID for adding the auto_replies_enabled column to the guild_settings table
+			Migrate: func(tx *gorm.DB) error {
+				type GuildSettings struct {
+					GuildID            string `gorm:"primaryKey"`
+					SuggestionsEnabled bool
+					AutoRepliesEnabled bool
+					UpdatedAt          time.Time
+				}
+				if err := tx.AutoMigrate(&GuildSettings{}); err != nil {
+					return err
+				}
+				// Auto-replies start out enabled in every guild
+				return tx.Exec("UPDATE guild_settings SET auto_replies_enabled = ?", true).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn("guild_settings", "auto_replies_enabled")
+			},
+		},
+		{
+			ID: "202610191300", // Static ID for making term titles unique regardless of case among active terms
+			Migrate: func(tx *gorm.DB) error {
+				// Fails if two active terms differ only in case; rename or remove one first
+				if err := tx.Exec("DROP INDEX IF EXISTS idx_terms_term").Error; err != nil {
+					return err
+				}
+				return tx.Exec(termTitleIndex).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec("DROP INDEX IF EXISTS idx_terms_term_active").Error; err != nil {
+					return err
+				}
+				return tx.Exec("CREATE UNIQUE INDEX idx_terms_term ON terms(term)").Error
+			},
+		},
 	})
 
 	// Run the migrations
 	if err := m.Migrate(); err != nil {
-		dbLogger.WithError(err).Error("Could not migrate")
+		logger.WithError(err).Error("Could not migrate")
 		return err
 	}
 
-	dbLogger.Info("Migration ran successfully")
+	logger.Info("Migration ran successfully")
 	return nil
 }
 
 func RunMigrations(db *gorm.DB) error {
-	return db.AutoMigrate(&Term{}, &Player{}, &GiftCodeRedemption{})
+	if err := db.AutoMigrate(&Term{}, &TermAlias{}, &TermRevision{}, &TermSuggestion{}, &TermTrigger{}, &Player{}, &GiftCodeRedemption{}, &GuildSettings{}, &AuditEvent{}, &ScrapeSiteState{}, &ScrapeSiteHealth{}, &DiscoveredCode{}, &NotificationRoute{}, &UserNotificationSettings{}); err != nil {
+		return err
+	}
+	return db.Exec(termTitleIndex).Error
 }
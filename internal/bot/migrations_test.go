@@ -0,0 +1,40 @@
+// File: internal/bot/migrations_test.go
+
+package bot
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestTermTitleIndex(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	if err := runMigrations(db, logger); err != nil {
+		t.Fatal(err)
+	}
+
+	insert := func(title string) error {
+		return db.Create(&Term{Term: title, Description: title}).Error
+	}
+	if err := insert("Furnace"); err != nil {
+		t.Fatal(err)
+	}
+	if err := insert("FURNACE"); err == nil {
+		t.Error("expected titles differing in case to conflict")
+	}
+	if err := db.Where("term = ?", "Furnace").Delete(&Term{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := insert("furnace"); err != nil {
+		t.Errorf("a removed term blocked its title: %v", err)
+	}
+}
@@ -4,10 +4,12 @@ package bot
 
 import (
 	"context"
+	"net/http"
 	"sync"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/patrickmn/go-cache"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -17,8 +19,80 @@ import (
 // Term represents a term in the database
 type Term struct {
 	gorm.Model
-	Term        string `gorm:"uniqueIndex;not null"`
+	Term        string `gorm:"not null"` // unique among active terms regardless of case, see termTitleIndex
 	Description string `gorm:"not null"`
+	Category    string `gorm:"index"`
+	AuthorID    string // Discord ID of whoever added the term, empty for older terms
+	Aliases     []TermAlias
+}
+
+// TermAlias is an alternative title a term can be looked up by
+type TermAlias struct {
+	ID     uint   `gorm:"primaryKey"`
+	TermID uint   `gorm:"index;not null"`
+	Alias  string `gorm:"uniqueIndex;not null"`
+}
+
+// TermTrigger is a keyword that makes the bot explain a term when it is
+// mentioned in an auto-reply channel. Triggers of removed terms are kept so
+// that restoring the term brings them back.
+type TermTrigger struct {
+	ID      uint   `gorm:"primaryKey"`
+	TermID  uint   `gorm:"index;not null"`
+	Keyword string `gorm:"uniqueIndex;not null"`
+}
+
+// Term revision actions
+const (
+	TermActionAdd      = "add"
+	TermActionEdit     = "edit"
+	TermActionRemove   = "remove"
+	TermActionRestore  = "restore"
+	TermActionRevert   = "revert"
+	TermActionAlias    = "alias"
+	TermActionUnalias  = "unalias"
+	TermActionCategory = "category"
+)
+
+// TermRevision is a snapshot of a term taken after every change to it
+type TermRevision struct {
+	ID          uint   `gorm:"primaryKey"`
+	TermID      uint   `gorm:"uniqueIndex:idx_term_revision;not null"`
+	Revision    int    `gorm:"uniqueIndex:idx_term_revision;not null"` // numbered from 1 per term
+	Action      string // one of the term revision actions
+	Note        string // e.g. the revision a revert went back to
+	Term        string
+	Description string
+	Category    string
+	Aliases     []string `gorm:"serializer:json"`
+	AuthorID    string
+	CreatedAt   time.Time
+}
+
+// Term suggestion statuses
+const (
+	TermSuggestionPending  = "pending"
+	TermSuggestionAccepted = "accepted"
+	TermSuggestionRejected = "rejected"
+	// TermSuggestionStale marks an edit that was accepted after the term had
+	// changed since the suggestion was made, so it was not applied
+	TermSuggestionStale = "stale"
+)
+
+// TermSuggestion is a change to the glossary proposed by a member without
+// edit rights, waiting for a glossary editor to accept or reject it
+type TermSuggestion struct {
+	ID          uint   `gorm:"primaryKey"`
+	Action      string // add, edit or remove
+	Term        string
+	Description string // proposed description, empty for removals
+	Previous    string // description when the change was suggested
+	SuggestedBy string // Discord ID of the member who suggested the change
+	ChannelID   string // channel the suggestion was made in
+	Status      string `gorm:"index"`
+	ReviewedBy  string // Discord ID of the reviewer
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // Player represents a player in the database
@@ -37,6 +111,27 @@ type GiftCodeRedemption struct {
 	RedeemedAt time.Time
 }
 
+// GuildSettings holds the per-guild feature toggles
+type GuildSettings struct {
+	GuildID            string `gorm:"primaryKey"`
+	SuggestionsEnabled bool
+	AutoRepliesEnabled bool
+	UpdatedAt          time.Time
+}
+
+// AuditEvent records an admin or data-changing action
+type AuditEvent struct {
+	ID        uint   `gorm:"primaryKey"`
+	ActorID   string `gorm:"index"`
+	ActorName string
+	GuildID   string `gorm:"index"`
+	Action    string `gorm:"index"`
+	Target    string
+	Before    string
+	After     string
+	CreatedAt time.Time `gorm:"index"`
+}
+
 // GiftCode represents the structure for gift codes
 type GiftCode struct {
 	Code        string
@@ -48,32 +143,204 @@ type GiftCode struct {
 
 // ScrapeSite represents a site configuration for scraping gift codes
 type ScrapeSite struct {
-	Name     string `mapstructure:"name"`
-	URL      string `mapstructure:"url"`
-	Selector string `mapstructure:"selector"`
+	Name string `mapstructure:"name"`
+	URL  string `mapstructure:"url"`
+	Type string `mapstructure:"type"` // html (default), json, rss, atom or regex
+	// html: CSS selector for the code elements, and optionally the attribute holding the code
+	Selector  string `mapstructure:"selector"`
+	Attribute string `mapstructure:"attribute"`
+	// json: dotted path to the code items (e.g. "data.codes[]") and the keys read from each item
+	Path           string `mapstructure:"path"`
+	CodeKey        string `mapstructure:"code_key"`
+	DescriptionKey string `mapstructure:"description_key"`
+	// rss, atom and regex: pattern matching a code; the first capture group is used if present
+	Pattern string `mapstructure:"pattern"`
+	// html: selector evaluated within the code element's parent whose text is the description
+	DescriptionSelector string `mapstructure:"description_selector"`
+	// Candidates not matching CodePattern are dropped; defaults to the gift_code length limits
+	CodePattern string `mapstructure:"code_pattern"`
+	// CodeCase is "upper", "lower" or empty to keep the case as scraped
+	CodeCase string `mapstructure:"code_case"`
+	// StripWhitespace removes whitespace inside codes, e.g. "ABC 123" becomes "ABC123"
+	StripWhitespace bool `mapstructure:"strip_whitespace"`
+	// Timeout, Retries and Interval override the scrape-wide defaults when set
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Retries  *int          `mapstructure:"retries"`
+	Interval time.Duration `mapstructure:"interval"`
+	// Trust is how much a listing on this site counts towards a code's confidence,
+	// from 0 to 1; defaults to scrape.confidence.default_trust
+	Trust float64 `mapstructure:"trust"`
+}
+
+// ScrapeChannel watches Discord channels, such as ones following the game's
+// announcement channel, for messages containing gift codes
+type ScrapeChannel struct {
+	Name       string   `mapstructure:"name"`
+	ChannelIDs []string `mapstructure:"channel_ids"`
+	// Pattern matches a code in message content and embeds; the first capture group is used if present
+	Pattern         string  `mapstructure:"pattern"`
+	CodePattern     string  `mapstructure:"code_pattern"`
+	CodeCase        string  `mapstructure:"code_case"`
+	StripWhitespace bool    `mapstructure:"strip_whitespace"`
+	Trust           float64 `mapstructure:"trust"`
+}
+
+// ScrapeWindow scrapes at a different interval during the given hours and
+// weekdays, or not at all when Skip is set
+type ScrapeWindow struct {
+	Hours    string        `mapstructure:"hours"`    // e.g. "14-18" or "9-12,20"
+	Weekdays string        `mapstructure:"weekdays"` // e.g. "mon-fri" or "sat,sun"; empty means every day
+	Interval time.Duration `mapstructure:"interval"`
+	Skip     bool          `mapstructure:"skip"` // quiet hours: no site is scraped, whatever its interval
+}
+
+// DigestSchedule is when a daily or weekly digest is posted.
+type DigestSchedule struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Time    string `mapstructure:"time"`    // time of day, e.g. "09:00"
+	Weekday string `mapstructure:"weekday"` // weekly digests only, e.g. "mon"
 }
 
 // ScrapeResult represents the result of a scrape operation
 type ScrapeResult struct {
 	SiteName string
+	Status   string // changed, unchanged or error
 	Codes    []GiftCode
+	Rejected int // candidates dropped because they did not match the code pattern
 	Error    error
 }
 
+// ScrapeSiteState keeps the conditional GET validators and the last parse of a scrape site
+type ScrapeSiteState struct {
+	SiteName     string `gorm:"primaryKey"`
+	ConfigHash   string
+	ETag         string
+	LastModified string
+	ContentHash  string
+	Codes        string // JSON encoded codes from the last parse
+	Rejected     int
+	CheckedAt    time.Time
+	ChangedAt    time.Time
+}
+
+// ScrapeSiteHealth tracks how reliably a scrape site produces codes
+type ScrapeSiteHealth struct {
+	SiteName            string `gorm:"primaryKey"`
+	Runs                int
+	SuccessfulRuns      int
+	ConsecutiveFailures int
+	LastSuccessAt       time.Time
+	LastErrorAt         time.Time
+	LastError           string
+	LastCodeCount       int
+	BaselineCodes       float64 // moving average of codes returned per successful run
+	Alerting            bool
+}
+
+// Discovered code statuses
+const (
+	CodeStatusPending   = "pending"
+	CodeStatusAnnounced = "announced"
+	CodeStatusRejected  = "rejected"
+	CodeStatusExpired   = "expired"
+)
+
+// Gift code API validation results
+const (
+	CodeValidationValid   = "valid"
+	CodeValidationInvalid = "invalid"
+)
+
+// DiscoveredCode is a scraped gift code together with the sources listing it
+// and how confident we are that it is real
+type DiscoveredCode struct {
+	Code              string `gorm:"primaryKey"`
+	Description       string
+	Sources           string // comma-separated site names
+	Confidence        float64
+	Validation        string // valid, invalid or empty when not checked
+	ValidationMessage string
+	Status            string `gorm:"index"`
+	ReviewedBy        string
+	SubmittedBy       string // Discord ID of the member who submitted the code, if any
+	SubmitChannelID   string
+	FirstSeenAt       time.Time
+	UpdatedAt         time.Time
+}
+
+// Notification event types
+const (
+	EventNewCode        = "new_code"
+	EventCodeExpired    = "code_expired"
+	EventDeployFinished = "deploy_finished"
+	EventScraperBroken  = "scraper_broken"
+)
+
+// Notification route targets
+const (
+	RouteTargetChannel = "channel"
+	RouteTargetRole    = "role"
+	RouteTargetDM      = "dm"
+	RouteTargetWebhook = "webhook"
+)
+
+// NotificationRoute delivers one type of event to a target. Routes come from
+// the config file or are added at runtime and stored in the database.
+type NotificationRoute struct {
+	ID        uint     `gorm:"primaryKey" mapstructure:"-"`
+	Name      string   `mapstructure:"name"`
+	Event     string   `gorm:"index" mapstructure:"event"`
+	Target    string   `mapstructure:"target"`     // channel, role, dm or webhook
+	ChannelID string   `mapstructure:"channel_id"` // channel and role targets
+	RoleID    string   `mapstructure:"role_id"`    // role target, mentioned in ChannelID
+	UserIDs   []string `gorm:"serializer:json" mapstructure:"user_ids"`
+	URL       string   `mapstructure:"url"` // webhook target, receives a JSON payload
+	// Template is a Go text/template rendered with the event data; empty uses the event's default
+	Template  string    `mapstructure:"template"`
+	CreatedBy string    `mapstructure:"-"`
+	CreatedAt time.Time `mapstructure:"-"`
+}
+
+// UserNotificationSettings stores a member's notification preferences and
+// the delivery status of their DMs.
+type UserNotificationSettings struct {
+	DiscordID     string `gorm:"primaryKey"`
+	DMEnabled     bool   `gorm:"index"`
+	DMFailures    int    // failed DMs in a row
+	DMPaused      bool   // set when DMs are closed or keep failing
+	LastDMError   string
+	LastDMErrorAt time.Time
+	UpdatedAt     time.Time
+}
+
 // Bot Models
 type Bot struct {
-	Config           *Config
-	Session          *discordgo.Session
-	DB               *gorm.DB
-	logger           *logrus.Logger
-	HandlerRegistry  map[string]CommandHandler
-	ctx              context.Context
-	cancel           context.CancelFunc
-	lastCheckedCodes []GiftCode
-	scrapeMutex      sync.Mutex
-	Code             string
-	Description      string
-	Source           string
+	Config            *Config
+	Session           *discordgo.Session
+	DB                *gorm.DB
+	logger            *logrus.Logger
+	HandlerRegistry   map[string]CommandHandler
+	componentHandlers map[string]ComponentHandler
+	commands          map[string]*Command
+	categories        []*CommandCategory
+	commandsModTime   time.Time // modification time of the loaded commands file
+	commandsMutex     sync.RWMutex
+	cooldowns         *cache.Cache
+	cooldownMutex     sync.Mutex
+	autoReplies       *cache.Cache // channel and term cooldowns of auto-replies
+	autoReplyMutex    sync.Mutex
+	triggers          []TermTrigger // cached trigger keywords, nil until loaded
+	triggersMutex     sync.RWMutex
+	paginators        *cache.Cache
+	glossaryImports   *cache.Cache // glossary imports waiting for confirmation
+	ctx               context.Context
+	cancel            context.CancelFunc
+	scrapeMutex       sync.Mutex
+	channelWatches    map[string]*channelWatch
+	termSearch        bool // terms are searched with the FTS5 index
+	scrapeClient      *http.Client
+	webhookClient     *http.Client
+	dms               chan queuedDM // DMs waiting for the DM worker
 }
 
 // Command represents a bot command and its attributes
@@ -84,10 +351,21 @@ type Command struct {
 	Cooldown    string
 	Handler     string
 	Hidden      bool
+	Aliases     []string
+	Category    string
+	Permission  string
+	Examples    []string
 	Subcommands map[string]*Command
 	HandlerFunc func(*discordgo.Session, *discordgo.MessageCreate, []string, *Command)
 }
 
+// CommandCategory groups commands in the help output
+type CommandCategory struct {
+	Name        string
+	Title       string
+	Description string
+}
+
 // Configuration Models
 
 type Config struct {
@@ -100,7 +378,21 @@ type Config struct {
 		Enabled               bool   `mapstructure:"enabled"`
 		CommandPrefix         string `mapstructure:"command_prefix"`
 		NotificationChannelID string `mapstructure:"notification_channel_id"`
+		AuditChannelID        string `mapstructure:"audit_channel_id"`
+		AdminChannelID        string `mapstructure:"admin_channel_id"`
 	} `mapstructure:"discord"`
+	Notifications struct {
+		Routes            []NotificationRoute `mapstructure:"routes"`
+		WebhookTimeout    time.Duration       `mapstructure:"webhook_timeout"`
+		WebhookRetries    int                 `mapstructure:"webhook_retries"`
+		WebhookRetryDelay time.Duration       `mapstructure:"webhook_retry_delay"`
+	} `mapstructure:"notifications"`
+	Digest struct {
+		ChannelID string         `mapstructure:"channel_id"` // defaults to discord.admin_channel_id
+		Timezone  string         `mapstructure:"timezone"`
+		Daily     DigestSchedule `mapstructure:"daily"`
+		Weekly    DigestSchedule `mapstructure:"weekly"`
+	} `mapstructure:"digest"`
 	Server struct {
 		Port string `mapstructure:"port"`
 	} `mapstructure:"server"`
@@ -123,6 +415,51 @@ type Config struct {
 		APITimeout  time.Duration `mapstructure:"api_timeout"`
 	} `mapstructure:"gift_code"`
 	Scrape struct {
-		Sites []ScrapeSite `mapstructure:"sites"`
+		Sites       []ScrapeSite    `mapstructure:"sites"`
+		Channels    []ScrapeChannel `mapstructure:"channels"`
+		Concurrency int             `mapstructure:"concurrency"`
+		Timeout     time.Duration   `mapstructure:"timeout"`
+		Retries     int             `mapstructure:"retries"`
+		RetryDelay  time.Duration   `mapstructure:"retry_delay"`
+		UserAgent   string          `mapstructure:"user_agent"`
+		Schedule    struct {
+			Interval   time.Duration  `mapstructure:"interval"`
+			RunOnStart bool           `mapstructure:"run_on_start"`
+			Jitter     time.Duration  `mapstructure:"jitter"`
+			Timezone   string         `mapstructure:"timezone"`
+			Windows    []ScrapeWindow `mapstructure:"windows"`
+		} `mapstructure:"schedule"`
+		Fixtures struct {
+			Dir    string `mapstructure:"dir"`
+			Record bool   `mapstructure:"record"` // save every fetched response as a fixture
+			Replay bool   `mapstructure:"replay"` // read responses from fixtures instead of the network
+		} `mapstructure:"fixtures"`
+		Confidence struct {
+			Threshold        float64 `mapstructure:"threshold"`
+			DefaultTrust     float64 `mapstructure:"default_trust"`
+			ValidatePlayerID string  `mapstructure:"validate_player_id"` // validating redeems the code for this player
+			AutoDeploy       bool    `mapstructure:"auto_deploy"`
+		} `mapstructure:"confidence"`
+		Health struct {
+			FailureThreshold int     `mapstructure:"failure_threshold"`
+			BaselineWindow   int     `mapstructure:"baseline_window"`
+			MinBaseline      float64 `mapstructure:"min_baseline"`
+		} `mapstructure:"health"`
 	} `mapstructure:"scrape"`
+	Suggestions struct {
+		Enabled     bool `mapstructure:"enabled"`
+		MaxDistance int  `mapstructure:"max_distance"`
+		MaxResults  int  `mapstructure:"max_results"`
+	} `mapstructure:"suggestions"`
+	Glossary struct {
+		EditorRoleID    string `mapstructure:"editor_role_id"`    // members with this role may change any term
+		CreatorsCanEdit bool   `mapstructure:"creators_can_edit"` // members may change the terms they added
+		ReviewChannelID string `mapstructure:"review_channel_id"` // defaults to discord.admin_channel_id
+	} `mapstructure:"glossary"`
+	AutoReply struct {
+		ChannelIDs      []string      `mapstructure:"channel_ids"`      // channels terms are explained in; none turns auto-replies off
+		ChannelInterval time.Duration `mapstructure:"channel_interval"` // minimum time between replies in a channel
+		TermCooldown    time.Duration `mapstructure:"term_cooldown"`    // minimum time before a term is explained again in a channel
+		MaxTerms        int           `mapstructure:"max_terms"`        // terms explained per reply
+	} `mapstructure:"auto_reply"`
 }
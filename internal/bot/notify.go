@@ -0,0 +1,376 @@
+// File: internal/bot/notify.go
+
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Notification is an event delivered to every route registered for its type.
+// Data is the template data and the "data" field of webhook payloads.
+// Discord targets also get the embed and components, when set.
+type Notification struct {
+	Event      string
+	Data       interface{}
+	Embed      *discordgo.MessageEmbed
+	Components []discordgo.MessageComponent
+}
+
+// DeployReport is the data of a deploy_finished notification.
+type DeployReport struct {
+	Code      string
+	Succeeded int
+	Total     int
+}
+
+// ScraperAlert is the data of a scraper_broken notification.
+type ScraperAlert struct {
+	Site    string
+	Message string
+	Error   string
+}
+
+// defaultTemplates are used by routes without their own template.
+var defaultTemplates = map[string]string{
+	EventNewCode:        "🎉 New gift code: **{{.Code}}**{{if .Description}} ({{.Description}}){{end}}",
+	EventCodeExpired:    "⌛ Gift code **{{.Code}}** has expired.",
+	EventDeployFinished: "🚀 Deploy of **{{.Code}}** finished: {{.Succeeded}} of {{.Total}} players succeeded.",
+	EventScraperBroken:  "{{.Message}}",
+}
+
+// NotificationEvents lists the event types routes can subscribe to.
+func NotificationEvents() []string {
+	return []string{EventNewCode, EventCodeExpired, EventDeployFinished, EventScraperBroken}
+}
+
+// webhookPayload is the JSON body posted to webhook routes.
+type webhookPayload struct {
+	Event     string      `json:"event"`
+	Message   string      `json:"message"`
+	Data      interface{} `json:"data"`
+	Timestamp string      `json:"timestamp"`
+}
+
+// ValidateRoute checks that a route has a known event, a known target and the
+// fields its target needs, and that its template parses.
+func ValidateRoute(route NotificationRoute) error {
+	if _, ok := defaultTemplates[route.Event]; !ok {
+		return fmt.Errorf("unknown event '%s' (use one of %s)", route.Event, strings.Join(NotificationEvents(), ", "))
+	}
+	switch route.Target {
+	case RouteTargetChannel:
+		if route.ChannelID == "" {
+			return fmt.Errorf("channel routes need a channel")
+		}
+	case RouteTargetRole:
+		if route.ChannelID == "" || route.RoleID == "" {
+			return fmt.Errorf("role routes need a channel and a role")
+		}
+	case RouteTargetDM:
+		if len(route.UserIDs) == 0 {
+			return fmt.Errorf("dm routes need at least one user")
+		}
+	case RouteTargetWebhook:
+		if !strings.HasPrefix(route.URL, "http://") && !strings.HasPrefix(route.URL, "https://") {
+			return fmt.Errorf("webhook routes need an http(s) URL")
+		}
+	default:
+		return fmt.Errorf("unknown target '%s' (use channel, role, dm or webhook)", route.Target)
+	}
+	if _, err := template.New("route").Parse(route.Template); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
+
+// NotificationRoutes returns the routes from the config file followed by the
+// ones stored in the database. Without any new_code route, the legacy
+// notification_channel_id is used as one.
+func (b *Bot) NotificationRoutes() ([]NotificationRoute, error) {
+	var stored []NotificationRoute
+	if err := b.DB.Order("id").Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("error loading notification routes: %w", err)
+	}
+	routes := append(append([]NotificationRoute{}, b.Config.Notifications.Routes...), stored...)
+
+	for _, route := range routes {
+		if route.Event == EventNewCode {
+			return routes, nil
+		}
+	}
+	if channelID := b.Config.Discord.NotificationChannelID; channelID != "" {
+		routes = append(routes, NotificationRoute{
+			Name:      "notification_channel_id",
+			Event:     EventNewCode,
+			Target:    RouteTargetChannel,
+			ChannelID: channelID,
+		})
+	}
+	return routes, nil
+}
+
+// AddNotificationRoute validates and stores a route.
+func (b *Bot) AddNotificationRoute(route *NotificationRoute, actor Actor) error {
+	if err := ValidateRoute(*route); err != nil {
+		return err
+	}
+	if err := b.DB.Create(route).Error; err != nil {
+		return fmt.Errorf("error saving notification route: %w", err)
+	}
+	b.audit(actor, "notify.route.add", strconv.FormatUint(uint64(route.ID), 10), "", route.Describe())
+	return nil
+}
+
+// RemoveNotificationRoute deletes a stored route. Routes from the config file
+// can only be removed there.
+func (b *Bot) RemoveNotificationRoute(id uint, actor Actor) (NotificationRoute, error) {
+	var route NotificationRoute
+	if err := b.DB.First(&route, id).Error; err != nil {
+		return route, fmt.Errorf("no notification route with ID %d", id)
+	}
+	if err := b.DB.Delete(&route).Error; err != nil {
+		return route, fmt.Errorf("error removing notification route: %w", err)
+	}
+	b.audit(actor, "notify.route.remove", strconv.FormatUint(uint64(id), 10), route.Describe(), "")
+	return route, nil
+}
+
+// Notify delivers a notification to every route for its event. Deliveries run
+// in the background so that slow webhooks don't hold up the caller.
+func (b *Bot) Notify(n Notification) {
+	routes, err := b.NotificationRoutes()
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("event", n.Event).Error("Error loading notification routes")
+		return
+	}
+
+	delivered := 0
+	for _, route := range routes {
+		if route.Event != n.Event {
+			continue
+		}
+		delivered++
+		go func(route NotificationRoute) {
+			if err := b.DeliverNotification(route, n); err != nil {
+				b.GetLogger().WithError(err).
+					WithField("event", n.Event).
+					WithField("route", route.Describe()).
+					Error("Error delivering notification")
+			}
+		}(route)
+	}
+	if delivered == 0 {
+		b.GetLogger().WithField("event", n.Event).Debug("No notification routes for event")
+	}
+}
+
+// DeliverNotification sends a notification to a single route.
+func (b *Bot) DeliverNotification(route NotificationRoute, n Notification) error {
+	text, err := renderRoute(route, n)
+	if err != nil {
+		return err
+	}
+
+	switch route.Target {
+	case RouteTargetChannel:
+		return b.sendNotification(route.ChannelID, discordContent(route, n, text), n, nil)
+	case RouteTargetRole:
+		content := strings.TrimSpace(fmt.Sprintf("<@&%s> %s", route.RoleID, discordContent(route, n, text)))
+		return b.sendNotification(route.ChannelID, content, n, &discordgo.MessageAllowedMentions{Roles: []string{route.RoleID}})
+	case RouteTargetDM:
+		return b.sendDMs(route.UserIDs, discordContent(route, n, text), n)
+	case RouteTargetWebhook:
+		return b.postWebhook(route, n, text)
+	default:
+		return fmt.Errorf("unknown route target '%s'", route.Target)
+	}
+}
+
+// renderRoute renders the route's template, or the event's default one.
+func renderRoute(route NotificationRoute, n Notification) (string, error) {
+	text := route.Template
+	if text == "" {
+		text = defaultTemplates[n.Event]
+	}
+	tmpl, err := template.New(n.Event).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, n.Data); err != nil {
+		return "", fmt.Errorf("error rendering template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// discordContent returns the message text for Discord targets. Notifications
+// with an embed don't repeat the default text unless the route has a template.
+func discordContent(route NotificationRoute, n Notification, text string) string {
+	if n.Embed != nil && route.Template == "" {
+		return ""
+	}
+	return text
+}
+
+func (b *Bot) sendNotification(channelID, content string, n Notification, mentions *discordgo.MessageAllowedMentions) error {
+	if b.Session == nil {
+		return fmt.Errorf("discord is disabled")
+	}
+	message := notificationMessage(content, n)
+	message.AllowedMentions = mentions
+	if _, err := b.Session.ChannelMessageSendComplex(channelID, message); err != nil {
+		return fmt.Errorf("error sending to channel %s: %w", channelID, err)
+	}
+	return nil
+}
+
+func notificationMessage(content string, n Notification) *discordgo.MessageSend {
+	message := &discordgo.MessageSend{
+		Content:    content,
+		Components: n.Components,
+	}
+	if n.Embed != nil {
+		message.Embeds = []*discordgo.MessageEmbed{n.Embed}
+	}
+	return message
+}
+
+// sendDMs sends the notification to each user, continuing past users whose
+// DMs are closed or paused. The last error is returned.
+func (b *Bot) sendDMs(userIDs []string, content string, n Notification) error {
+	var lastErr error
+	for _, userID := range userIDs {
+		if err := b.sendDM(userID, notificationMessage(content, n)); err != nil {
+			lastErr = err
+			b.GetLogger().WithError(err).WithField("user", userID).Warn("Error sending notification DM")
+		}
+	}
+	return lastErr
+}
+
+// postWebhook posts the notification as JSON, retrying network errors, 429s
+// and 5xx responses with a growing delay.
+func (b *Bot) postWebhook(route NotificationRoute, n Notification, text string) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:     n.Event,
+		Message:   text,
+		Data:      n.Data,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	retries := b.Config.Notifications.WebhookRetries
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(b.Config.Notifications.WebhookRetryDelay * time.Duration(attempt)):
+			case <-b.ctx.Done():
+				return b.ctx.Err()
+			}
+		}
+
+		retryable, err := b.postWebhookOnce(route.URL, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+		b.GetLogger().WithError(err).WithField("url", route.URL).Warnf("Webhook delivery failed (attempt %d of %d)", attempt+1, retries+1)
+	}
+	return lastErr
+}
+
+func (b *Bot) postWebhookOnce(url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(b.ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", b.Config.Scrape.UserAgent)
+
+	resp, err := b.webhookClient.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("error posting webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("unexpected webhook status: %s", resp.Status)
+	}
+	return false, nil
+}
+
+// Describe renders a route as a single line for lists and logs.
+func (r NotificationRoute) Describe() string {
+	var target string
+	switch r.Target {
+	case RouteTargetChannel:
+		target = fmt.Sprintf("<#%s>", r.ChannelID)
+	case RouteTargetRole:
+		target = fmt.Sprintf("<@&%s> in <#%s>", r.RoleID, r.ChannelID)
+	case RouteTargetDM:
+		mentions := make([]string, len(r.UserIDs))
+		for i, userID := range r.UserIDs {
+			mentions[i] = fmt.Sprintf("<@%s>", userID)
+		}
+		target = "DM " + strings.Join(mentions, " ")
+	case RouteTargetWebhook:
+		target = r.URL
+	}
+	line := fmt.Sprintf("%s → %s %s", r.Event, r.Target, target)
+	if r.Template != "" {
+		line += fmt.Sprintf(" (template: %s)", Truncate(r.Template, 60))
+	}
+	return line
+}
+
+// SampleNotification builds an example notification for testing a route.
+func (b *Bot) SampleNotification(event string) Notification {
+	code := DiscoveredCode{
+		Code:        "EXAMPLE123",
+		Description: "Example rewards",
+		Sources:     "Example site",
+		Confidence:  1,
+		Status:      CodeStatusAnnounced,
+		FirstSeenAt: time.Now(),
+	}
+	switch event {
+	case EventNewCode:
+		return b.newCodeNotification(code)
+	case EventCodeExpired:
+		return Notification{Event: event, Data: code}
+	case EventDeployFinished:
+		return Notification{Event: event, Data: DeployReport{Code: code.Code, Succeeded: 9, Total: 10}}
+	default:
+		return Notification{Event: event, Data: ScraperAlert{Site: "Example site", Message: "🚨 Scraper **Example site** has failed 3 times in a row", Error: "unexpected status: 503 Service Unavailable"}}
+	}
+}
+
+// notifyCodeExpired marks an announced code as expired and notifies about it
+// once, the first time the gift code API reports it expired.
+func (b *Bot) notifyCodeExpired(code string) {
+	record, isNew, err := b.loadDiscoveredCode(code)
+	if err != nil || isNew || record.Status != CodeStatusAnnounced {
+		return
+	}
+	record.Status = CodeStatusExpired
+	if err := b.DB.Save(&record).Error; err != nil {
+		b.GetLogger().WithError(err).WithField("code", code).Error("Error marking gift code expired")
+		return
+	}
+	b.Notify(Notification{Event: EventCodeExpired, Data: record})
+}
@@ -0,0 +1,94 @@
+// File: internal/bot/notify_test.go
+
+package bot
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestValidateRoute(t *testing.T) {
+	tests := []struct {
+		name    string
+		route   NotificationRoute
+		wantErr bool
+	}{
+		{"channel", NotificationRoute{Event: EventNewCode, Target: RouteTargetChannel, ChannelID: "1"}, false},
+		{"role", NotificationRoute{Event: EventCodeExpired, Target: RouteTargetRole, ChannelID: "1", RoleID: "2"}, false},
+		{"dm", NotificationRoute{Event: EventDeployFinished, Target: RouteTargetDM, UserIDs: []string{"3"}}, false},
+		{"webhook", NotificationRoute{Event: EventScraperBroken, Target: RouteTargetWebhook, URL: "https://example.com/hook"}, false},
+		{"template", NotificationRoute{Event: EventNewCode, Target: RouteTargetChannel, ChannelID: "1", Template: "{{.Code}}"}, false},
+		{"unknown event", NotificationRoute{Event: "new_player", Target: RouteTargetChannel, ChannelID: "1"}, true},
+		{"unknown target", NotificationRoute{Event: EventNewCode, Target: "email"}, true},
+		{"channel without channel", NotificationRoute{Event: EventNewCode, Target: RouteTargetChannel}, true},
+		{"role without role", NotificationRoute{Event: EventNewCode, Target: RouteTargetRole, ChannelID: "1"}, true},
+		{"dm without users", NotificationRoute{Event: EventNewCode, Target: RouteTargetDM}, true},
+		{"webhook without scheme", NotificationRoute{Event: EventNewCode, Target: RouteTargetWebhook, URL: "example.com"}, true},
+		{"broken template", NotificationRoute{Event: EventNewCode, Target: RouteTargetChannel, ChannelID: "1", Template: "{{.Code"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoute(tt.route)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderRoute(t *testing.T) {
+	code := DiscoveredCode{Code: "ALPHA", Description: "Gems"}
+	tests := []struct {
+		name    string
+		route   NotificationRoute
+		n       Notification
+		want    string
+		wantErr bool
+	}{
+		{"default new code", NotificationRoute{}, Notification{Event: EventNewCode, Data: code},
+			"🎉 New gift code: **ALPHA** (Gems)", false},
+		{"default without description", NotificationRoute{}, Notification{Event: EventNewCode, Data: DiscoveredCode{Code: "BETA"}},
+			"🎉 New gift code: **BETA**", false},
+		{"default deploy", NotificationRoute{}, Notification{Event: EventDeployFinished, Data: DeployReport{Code: "ALPHA", Succeeded: 2, Total: 3}},
+			"🚀 Deploy of **ALPHA** finished: 2 of 3 players succeeded.", false},
+		{"default scraper", NotificationRoute{}, Notification{Event: EventScraperBroken, Data: ScraperAlert{Message: "broken"}},
+			"broken", false},
+		{"route template", NotificationRoute{Template: "{{.Code}} is out"}, Notification{Event: EventNewCode, Data: code},
+			"ALPHA is out", false},
+		{"missing field", NotificationRoute{Template: "{{.Site}}"}, Notification{Event: EventNewCode, Data: code},
+			"", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderRoute(tt.route, tt.n)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("renderRoute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscordContent(t *testing.T) {
+	embed := &discordgo.MessageEmbed{Title: "New code"}
+	tests := []struct {
+		name  string
+		route NotificationRoute
+		n     Notification
+		want  string
+	}{
+		{"text only", NotificationRoute{}, Notification{}, "text"},
+		{"embed replaces default text", NotificationRoute{}, Notification{Embed: embed}, ""},
+		{"embed keeps route template", NotificationRoute{Template: "{{.Code}}"}, Notification{Embed: embed}, "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := discordContent(tt.route, tt.n, "text"); got != tt.want {
+				t.Errorf("discordContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
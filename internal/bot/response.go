@@ -0,0 +1,255 @@
+// File: internal/bot/response.go
+
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// MessageLimit is Discord's maximum message content length.
+	MessageLimit = 2000
+	// embedDescriptionLimit is Discord's maximum embed description length.
+	embedDescriptionLimit = 4096
+	// embedFieldLimit is Discord's maximum embed field value length.
+	embedFieldLimit = 1024
+	// paginatorTTL is how long the Prev/Next buttons of a paginated response keep working.
+	paginatorTTL = 15 * time.Minute
+	// paginatorComponent is the custom ID prefix for paginator buttons.
+	paginatorComponent = "page"
+)
+
+// paginator holds the rendered pages of one paginated response.
+type paginator struct {
+	ownerID string
+	title   string
+	pages   []string
+}
+
+// Truncate shortens s to at most limit characters, ending it with "..." when
+// it is cut. Like Discord's limits, it counts characters rather than bytes, so
+// it never cuts a character in half.
+func Truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	if limit <= 3 {
+		head, _ := splitAtRune(s, limit)
+		return head
+	}
+	head, _ := splitAtRune(s, limit-3)
+	return head + "..."
+}
+
+// splitAtRune splits s after its first n characters.
+func splitAtRune(s string, n int) (string, string) {
+	i := 0
+	for ; n > 0 && i < len(s); n-- {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return s[:i], s[i:]
+}
+
+// SplitMessage splits content into chunks of at most limit characters,
+// breaking on line boundaries. Lines that are longer than limit on their own
+// are hard-split.
+func SplitMessage(content string, limit int) []string {
+	if utf8.RuneCountInString(content) <= limit {
+		return []string{content}
+	}
+
+	var chunks []string
+	var current strings.Builder
+	length := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			length = 0
+		}
+	}
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		for utf8.RuneCountInString(line) > limit {
+			flush()
+			var chunk string
+			chunk, line = splitAtRune(line, limit)
+			chunks = append(chunks, chunk)
+		}
+		lineLength := utf8.RuneCountInString(line)
+		if length+lineLength > limit {
+			flush()
+		}
+		current.WriteString(line)
+		length += lineLength
+	}
+	flush()
+	return chunks
+}
+
+// SendLongMessage sends content to a channel, splitting it across several
+// messages when it exceeds Discord's message limit.
+func (b *Bot) SendLongMessage(s *discordgo.Session, channelID, content string) error {
+	for _, chunk := range SplitMessage(content, MessageLimit) {
+		if err := b.SendMessage(s, channelID, chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paginate groups lines into pages of at most perPage lines, also keeping
+// each page within the embed description limit.
+func paginate(lines []string, perPage int) []string {
+	return packLines(lines, perPage, embedDescriptionLimit)
+}
+
+// EmbedFields lists lines in fields titled name, starting a "(continued)"
+// field whenever the field value limit would be exceeded.
+func EmbedFields(name string, lines []string) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+	for i, value := range packLines(lines, 0, embedFieldLimit) {
+		fieldName := name
+		if i > 0 {
+			fieldName += " (continued)"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: fieldName, Value: value})
+	}
+	return fields
+}
+
+// packLines groups lines into chunks of at most perPage lines (no limit when
+// perPage is 0) and at most limit characters each, newlines included.
+func packLines(lines []string, perPage, limit int) []string {
+	var pages []string
+	var current strings.Builder
+	count, length := 0, 0
+	for _, line := range lines {
+		line = Truncate(line, limit-1)
+		lineLength := utf8.RuneCountInString(line) + 1
+		if (perPage > 0 && count == perPage) || length+lineLength > limit {
+			pages = append(pages, current.String())
+			current.Reset()
+			count, length = 0, 0
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		count++
+		length += lineLength
+	}
+	if current.Len() > 0 || len(pages) == 0 {
+		pages = append(pages, current.String())
+	}
+	return pages
+}
+
+// SendPaginated sends lines as an embed split into pages of perPage lines.
+// When there is more than one page, Prev/Next buttons are attached that only
+// the invoking user (ownerID) can use. startPage is 1-based.
+func (b *Bot) SendPaginated(s *discordgo.Session, channelID, ownerID, title string, lines []string, perPage, startPage int) error {
+	p := &paginator{
+		ownerID: ownerID,
+		title:   title,
+		pages:   paginate(lines, perPage),
+	}
+	page := clampPage(startPage-1, len(p.pages))
+
+	id := newComponentToken()
+	if len(p.pages) > 1 {
+		b.paginators.Set(id, p, paginatorTTL)
+	}
+
+	_, err := s.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds:     []*discordgo.MessageEmbed{p.embed(page)},
+		Components: p.components(id, page),
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Error sending paginated message")
+	}
+	return err
+}
+
+func (b *Bot) handlePaginatorComponent(s *discordgo.Session, i *discordgo.InteractionCreate, args []string) {
+	if len(args) != 2 {
+		return
+	}
+	id := args[0]
+	cached, found := b.paginators.Get(id)
+	if !found {
+		b.RespondEphemeral(s, i, "⚠️ This list has expired. Run the command again.")
+		return
+	}
+	p := cached.(*paginator)
+	if InteractionUserID(i) != p.ownerID {
+		b.RespondEphemeral(s, i, "𐄂 Only the person who ran this command can change pages.")
+		return
+	}
+
+	page, err := strconv.Atoi(args[1])
+	if err != nil {
+		return
+	}
+	page = clampPage(page, len(p.pages))
+
+	components := p.components(id, page)
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{p.embed(page)},
+			Components: components,
+		},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Error updating paginated message")
+	}
+}
+
+func (p *paginator) embed(page int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       p.title,
+		Description: p.pages[page],
+	}
+	if len(p.pages) > 1 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("Page %d of %d", page+1, len(p.pages))}
+	}
+	return embed
+}
+
+func (p *paginator) components(id string, page int) []discordgo.MessageComponent {
+	if len(p.pages) <= 1 {
+		return nil
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{
+				Label:    "◀ Prev",
+				Style:    discordgo.SecondaryButton,
+				CustomID: ComponentID(paginatorComponent, id, strconv.Itoa(page-1)),
+				Disabled: page == 0,
+			},
+			discordgo.Button{
+				Label:    "Next ▶",
+				Style:    discordgo.SecondaryButton,
+				CustomID: ComponentID(paginatorComponent, id, strconv.Itoa(page+1)),
+				Disabled: page == len(p.pages)-1,
+			},
+		}},
+	}
+}
+
+func clampPage(page, total int) int {
+	if page < 0 {
+		return 0
+	}
+	if page >= total {
+		return total - 1
+	}
+	return page
+}
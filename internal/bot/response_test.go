@@ -0,0 +1,77 @@
+// File: internal/bot/response_test.go
+
+package bot
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		limit   int
+		want    []string
+	}{
+		{"short", "hello", 10, []string{"hello"}},
+		{"exact", "0123456789", 10, []string{"0123456789"}},
+		{"lines", "aaaa\nbbbb\ncccc", 10, []string{"aaaa\nbbbb\n", "cccc"}},
+		{"long line", "0123456789abcde", 10, []string{"0123456789", "abcde"}},
+		{"long line after short", "ab\n0123456789abcde\ncd", 10, []string{"ab\n", "0123456789", "abcde\ncd"}},
+		{"characters not bytes", "ééééé\nüüüüü", 6, []string{"ééééé\n", "üüüüü"}},
+		{"hard split keeps characters whole", "日本語日本語日本", 3, []string{"日本語", "日本語", "日本"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMessage(tt.content, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitMessage(%q, %d) = %q, want %q", tt.content, tt.limit, got, tt.want)
+			}
+			if joined := strings.Join(got, ""); joined != tt.content {
+				t.Errorf("chunks join to %q, want %q", joined, tt.content)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		limit int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello world", 8, "hello..."},
+		{"héllo wörld", 8, "héllo..."},
+		{"hello", 2, "he"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.s, tt.limit); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestPackLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		perPage int
+		limit   int
+		want    []string
+	}{
+		{"empty", nil, 2, 100, []string{""}},
+		{"per page", []string{"a", "b", "c"}, 2, 100, []string{"a\nb\n", "c\n"}},
+		{"limit", []string{"aaaa", "bbbb", "cccc"}, 0, 10, []string{"aaaa\nbbbb\n", "cccc\n"}},
+		{"long line", []string{"0123456789"}, 0, 8, []string{"0123...\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := packLines(tt.lines, tt.perPage, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("packLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
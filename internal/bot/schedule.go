@@ -0,0 +1,314 @@
+// File: internal/bot/schedule.go
+
+package bot
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scrapeRunTimeout bounds a single scheduled scrape run.
+const scrapeRunTimeout = 5 * time.Minute
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// scrapeSchedule decides when each site is due, based on the configured
+// interval, windows and per-site overrides.
+type scrapeSchedule struct {
+	interval time.Duration
+	jitter   time.Duration
+	location *time.Location
+	windows  []scheduleWindow
+}
+
+type scheduleWindow struct {
+	hours    [24]bool
+	days     [7]bool
+	interval time.Duration
+	skip     bool // quiet hours
+}
+
+func newScrapeSchedule(config *Config) (*scrapeSchedule, error) {
+	cfg := config.Scrape.Schedule
+	schedule := &scrapeSchedule{
+		interval: cfg.Interval,
+		jitter:   cfg.Jitter,
+		location: time.UTC,
+	}
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrape timezone: %w", err)
+		}
+		schedule.location = location
+	}
+	for i, w := range cfg.Windows {
+		window, err := parseScheduleWindow(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrape window %d: %w", i+1, err)
+		}
+		schedule.windows = append(schedule.windows, window)
+	}
+	if schedule.alwaysQuiet() {
+		return nil, fmt.Errorf("scrape windows with skip cover the whole week")
+	}
+	return schedule, nil
+}
+
+// alwaysQuiet reports whether quiet hours leave no hour of the week to scrape in.
+func (s *scrapeSchedule) alwaysQuiet() bool {
+	for day := range 7 {
+		for hour := range 24 {
+			quiet := false
+			for _, window := range s.windows {
+				if window.skip && window.days[day] && window.hours[hour] {
+					quiet = true
+					break
+				}
+			}
+			if !quiet {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// parseScheduleWindow parses hour ranges such as "9-12,18" and weekday ranges
+// such as "mon-fri" or "sat,sun". An empty weekday list means every day.
+// Quiet hours (skip) have no interval.
+func parseScheduleWindow(w ScrapeWindow) (scheduleWindow, error) {
+	window := scheduleWindow{interval: w.Interval, skip: w.Skip}
+	switch {
+	case w.Skip && w.Interval != 0:
+		return window, fmt.Errorf("a window with skip can't have an interval")
+	case !w.Skip && w.Interval <= 0:
+		return window, fmt.Errorf("interval must be positive")
+	}
+
+	err := parseRanges(w.Hours, func(part string) (int, error) {
+		hour, err := strconv.Atoi(part)
+		if err != nil || hour < 0 || hour > 23 {
+			return 0, fmt.Errorf("invalid hour '%s'", part)
+		}
+		return hour, nil
+	}, 24, func(i int) { window.hours[i] = true })
+	if err != nil {
+		return window, err
+	}
+
+	if strings.TrimSpace(w.Weekdays) == "" {
+		for i := range window.days {
+			window.days[i] = true
+		}
+		return window, nil
+	}
+	err = parseRanges(w.Weekdays, func(part string) (int, error) {
+		day, ok := weekdayNames[strings.ToLower(part)]
+		if !ok {
+			return 0, fmt.Errorf("invalid weekday '%s'", part)
+		}
+		return int(day), nil
+	}, 7, func(i int) { window.days[i] = true })
+	return window, err
+}
+
+// parseRanges parses a comma-separated list of values or "from-to" ranges,
+// calling set for every value covered. Ranges may wrap around, e.g. "22-2".
+func parseRanges(spec string, parse func(string) (int, error), size int, set func(int)) error {
+	if strings.TrimSpace(spec) == "" {
+		return fmt.Errorf("empty range")
+	}
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := parse(strings.TrimSpace(from))
+		if err != nil {
+			return err
+		}
+		end := start
+		if isRange {
+			if end, err = parse(strings.TrimSpace(to)); err != nil {
+				return err
+			}
+		}
+		for i := start; ; i = (i + 1) % size {
+			set(i)
+			if i == end {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (w scheduleWindow) contains(t time.Time) bool {
+	return w.days[t.Weekday()] && w.hours[t.Hour()]
+}
+
+// windowAt returns the index of the first interval window containing t, or
+// -1, and whether t falls in quiet hours. Quiet hours take precedence over
+// interval windows.
+func (s *scrapeSchedule) windowAt(t time.Time) (int, bool) {
+	local := t.In(s.location)
+	index := -1
+	for i, window := range s.windows {
+		if !window.contains(local) {
+			continue
+		}
+		if window.skip {
+			return -1, true
+		}
+		if index < 0 {
+			index = i
+		}
+	}
+	return index, false
+}
+
+// intervalAt returns the scrape interval in effect at t for a site.
+func (s *scrapeSchedule) intervalAt(site ScrapeSite, t time.Time) time.Duration {
+	if site.Interval > 0 {
+		return site.Interval
+	}
+	if index, _ := s.windowAt(t); index >= 0 {
+		return s.windows[index].interval
+	}
+	return s.interval
+}
+
+// nextBoundary returns the next hour boundary after t at which a window opens
+// or closes, when the runs that are due have to be worked out again.
+func (s *scrapeSchedule) nextBoundary(t time.Time) (time.Time, bool) {
+	if len(s.windows) == 0 {
+		return time.Time{}, false
+	}
+	local := t.In(s.location)
+	hour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, s.location)
+	index, quiet := s.windowAt(hour)
+	for i := 1; i <= 7*24; i++ {
+		candidate := hour.Add(time.Duration(i) * time.Hour)
+		if nextIndex, nextQuiet := s.windowAt(candidate); nextIndex != index || nextQuiet != quiet {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// afterQuietHours moves t to the end of the quiet hours it falls in.
+func (s *scrapeSchedule) afterQuietHours(t time.Time) time.Time {
+	for i := 0; i < 7*24; i++ {
+		if _, quiet := s.windowAt(t); !quiet {
+			return t
+		}
+		boundary, ok := s.nextBoundary(t)
+		if !ok {
+			break
+		}
+		t = boundary
+	}
+	return t
+}
+
+// nextRun returns when a site last scraped at last should next be scraped,
+// using the interval in effect at now. Runs that are overdue because a
+// window with a shorter interval opened are due at now.
+func (s *scrapeSchedule) nextRun(site ScrapeSite, last, now time.Time) time.Time {
+	next := last.Add(s.intervalAt(site, now))
+	if next.Before(now) {
+		next = now
+	}
+	if s.jitter > 0 {
+		next = next.Add(rand.N(s.jitter))
+	}
+	return s.afterQuietHours(next)
+}
+
+// StartPeriodicScraping scrapes each site whenever it is due according to the
+// configured schedule, until the bot shuts down.
+func (b *Bot) StartPeriodicScraping() {
+	schedule, err := newScrapeSchedule(b.Config)
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Periodic scraping disabled")
+		return
+	}
+
+	go func() {
+		sites := b.Config.Scrape.Sites
+		if len(sites) == 0 {
+			b.GetLogger().Info("No scrape sites configured, periodic scraping stopped")
+			return
+		}
+
+		// Sites are tracked by their index in the config
+		now := time.Now()
+		last := make([]time.Time, len(sites))
+		next := make([]time.Time, len(sites))
+		for i, site := range sites {
+			last[i] = now
+			if b.Config.Scrape.Schedule.RunOnStart {
+				next[i] = schedule.afterQuietHours(now)
+			} else {
+				next[i] = schedule.nextRun(site, now, now)
+			}
+		}
+
+		for {
+			due := next[0]
+			for _, t := range next[1:] {
+				if t.Before(due) {
+					due = t
+				}
+			}
+			boundary, hasBoundary := schedule.nextBoundary(time.Now())
+			atBoundary := hasBoundary && boundary.Before(due)
+			if atBoundary {
+				due = boundary
+			}
+
+			timer := time.NewTimer(time.Until(due))
+			select {
+			case <-timer.C:
+			case <-b.ctx.Done():
+				timer.Stop()
+				b.GetLogger().Info("Stopping periodic scraping")
+				return
+			}
+
+			now := time.Now()
+			if atBoundary {
+				// The interval in effect or the quiet hours changed
+				for i, site := range sites {
+					next[i] = schedule.nextRun(site, last[i], now)
+				}
+			}
+
+			var dueSites []ScrapeSite
+			for i, site := range sites {
+				if !next[i].After(now) {
+					dueSites = append(dueSites, site)
+					last[i] = now
+					next[i] = schedule.nextRun(site, now, now)
+				}
+			}
+			if len(dueSites) == 0 {
+				continue
+			}
+
+			ctx, cancel := context.WithTimeout(b.ctx, scrapeRunTimeout)
+			results, err := b.ScrapeSites(ctx, dueSites)
+			if err != nil {
+				b.GetLogger().WithError(err).Error("Error during periodic scraping")
+			} else {
+				b.GetLogger().WithField("results", results).Info("Periodic scraping completed")
+			}
+			cancel()
+		}
+	}()
+}
@@ -0,0 +1,134 @@
+// File: internal/bot/schedule_test.go
+
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  ScrapeWindow
+		hours   []int
+		days    []time.Weekday
+		wantErr bool
+	}{
+		{"single hour", ScrapeWindow{Hours: "9", Interval: time.Minute}, []int{9}, nil, false},
+		{"range and list", ScrapeWindow{Hours: "9-11,20", Interval: time.Minute}, []int{9, 10, 11, 20}, nil, false},
+		{"wraps midnight", ScrapeWindow{Hours: "22-1", Interval: time.Minute}, []int{22, 23, 0, 1}, nil, false},
+		{"weekdays", ScrapeWindow{Hours: "0", Weekdays: "mon-wed", Interval: time.Minute}, []int{0},
+			[]time.Weekday{time.Monday, time.Tuesday, time.Wednesday}, false},
+		{"weekend wraps", ScrapeWindow{Hours: "0", Weekdays: "Sat-Sun", Interval: time.Minute}, []int{0},
+			[]time.Weekday{time.Saturday, time.Sunday}, false},
+		{"skip", ScrapeWindow{Hours: "3", Skip: true}, []int{3}, nil, false},
+		{"no interval", ScrapeWindow{Hours: "9"}, nil, nil, true},
+		{"skip with interval", ScrapeWindow{Hours: "9", Skip: true, Interval: time.Minute}, nil, nil, true},
+		{"bad hour", ScrapeWindow{Hours: "24", Interval: time.Minute}, nil, nil, true},
+		{"no hours", ScrapeWindow{Interval: time.Minute}, nil, nil, true},
+		{"bad weekday", ScrapeWindow{Hours: "1", Weekdays: "funday", Interval: time.Minute}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, err := parseScheduleWindow(tt.window)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hours [24]bool
+			for _, hour := range tt.hours {
+				hours[hour] = true
+			}
+			if window.hours != hours {
+				t.Errorf("hours = %v, want %v", window.hours, hours)
+			}
+			days := [7]bool{true, true, true, true, true, true, true}
+			if tt.days != nil {
+				days = [7]bool{}
+				for _, day := range tt.days {
+					days[day] = true
+				}
+			}
+			if window.days != days {
+				t.Errorf("days = %v, want %v", window.days, days)
+			}
+			if window.skip != tt.window.Skip {
+				t.Errorf("skip = %v, want %v", window.skip, tt.window.Skip)
+			}
+		})
+	}
+}
+
+func testSchedule(t *testing.T, windows ...ScrapeWindow) *scrapeSchedule {
+	t.Helper()
+	config := &Config{}
+	config.Scrape.Schedule.Interval = time.Hour
+	config.Scrape.Schedule.Windows = windows
+	schedule, err := newScrapeSchedule(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schedule
+}
+
+func TestScrapeSchedule(t *testing.T) {
+	schedule := testSchedule(t,
+		ScrapeWindow{Hours: "0-1", Interval: 10 * time.Minute},
+		ScrapeWindow{Hours: "3-5", Skip: true},
+	)
+	// 2024-01-01 is a Monday
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+	site := ScrapeSite{Name: "site"}
+
+	tests := []struct {
+		name       string
+		site       ScrapeSite
+		last, now  time.Time
+		want       time.Time
+		wantBorder time.Time
+	}{
+		{"default interval", site, at(12, 0), at(12, 0), at(13, 0), at(24, 0)},
+		{"window interval", site, at(0, 30), at(0, 30), at(0, 40), at(2, 0)},
+		{"window opened", site, at(23, 30), at(24, 0), at(24, 0), at(26, 0)},
+		{"window closed", site, at(1, 55), at(2, 0), at(2, 55), at(3, 0)},
+		{"moved past quiet hours", site, at(2, 30), at(2, 30), at(6, 0), at(3, 0)},
+		{"site interval", ScrapeSite{Interval: 5 * time.Minute}, at(0, 30), at(0, 30), at(0, 35), at(2, 0)},
+		{"site interval in quiet hours", ScrapeSite{Interval: 5 * time.Minute}, at(2, 58), at(2, 58), at(6, 0), at(3, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.nextRun(tt.site, tt.last, tt.now); !got.Equal(tt.want) {
+				t.Errorf("nextRun = %v, want %v", got, tt.want)
+			}
+			boundary, ok := schedule.nextBoundary(tt.now)
+			if !ok || !boundary.Equal(tt.wantBorder) {
+				t.Errorf("nextBoundary = %v, %v, want %v", boundary, ok, tt.wantBorder)
+			}
+		})
+	}
+}
+
+func TestScrapeScheduleAlwaysQuiet(t *testing.T) {
+	config := &Config{}
+	config.Scrape.Schedule.Interval = time.Hour
+	config.Scrape.Schedule.Windows = []ScrapeWindow{
+		{Hours: "0-11", Skip: true},
+		{Hours: "12-23", Skip: true},
+	}
+	if _, err := newScrapeSchedule(config); err == nil {
+		t.Error("expected an error for quiet hours covering the whole week")
+	}
+
+	config.Scrape.Schedule.Windows[1].Weekdays = "mon-sat"
+	if _, err := newScrapeSchedule(config); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -5,120 +5,257 @@ package bot
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/PuerkitoBio/goquery"
 )
 
+// ScrapeGiftCodes scrapes all configured sites.
 func (b *Bot) ScrapeGiftCodes(ctx context.Context) ([]ScrapeResult, error) {
-	var results []ScrapeResult
+	return b.ScrapeSites(ctx, b.Config.Scrape.Sites)
+}
 
-	for _, site := range b.Config.Scrape.Sites {
-		codes, err := b.scrapeSite(ctx, site)
-		results = append(results, ScrapeResult{
-			SiteName: site.Name,
-			Codes:    codes,
-			Error:    err,
-		})
-	}
-
-	newCodes := b.findNewCodes(results)
-	if len(newCodes) > 0 {
-		if err := b.notifyNewCodes(ctx, newCodes); err != nil {
-			b.GetLogger().WithError(err).Error("Error notifying new codes")
-		}
+// RunScrape scrapes sites on request of actor, recording the run in the
+// audit log.
+func (b *Bot) RunScrape(ctx context.Context, sites []ScrapeSite, actor Actor) ([]ScrapeResult, error) {
+	b.audit(actor, "scrape.run", b.scrapeTarget(sites), "", "")
+	return b.ScrapeSites(ctx, sites)
+}
+
+// scrapeTarget names the sites of a manual scrape for the audit log. It is
+// empty when all sites are scraped.
+func (b *Bot) scrapeTarget(sites []ScrapeSite) string {
+	if len(sites) == len(b.Config.Scrape.Sites) {
+		return ""
+	}
+	names := make([]string, len(sites))
+	for i, site := range sites {
+		names[i] = site.Name
 	}
+	return strings.Join(names, ", ")
+}
+
+// ScrapeSites scrapes the given sites in parallel, bounded by the configured
+// concurrency. Results are returned in the order of sites.
+func (b *Bot) ScrapeSites(ctx context.Context, sites []ScrapeSite) ([]ScrapeResult, error) {
+	results := make([]ScrapeResult, len(sites))
+
+	sem := make(chan struct{}, b.Config.Scrape.Concurrency)
+	var wg sync.WaitGroup
+	for i, site := range sites {
+		wg.Add(1)
+		go func(i int, site ScrapeSite) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i] = b.scrapeSite(ctx, site)
+		}(i, site)
+	}
+	wg.Wait()
+
+	b.updateScrapeHealth(results)
+	b.processDiscoveredCodes(ctx, results)
 
 	return results, nil
 }
 
-func (b *Bot) scrapeSite(ctx context.Context, site ScrapeSite) ([]GiftCode, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", site.URL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+// maxScrapeBodySize caps how much of a scraped response is read.
+const maxScrapeBodySize = 10 << 20
+
+// fetchResult is the outcome of a (conditional) GET of a scrape site.
+type fetchResult struct {
+	Body         []byte
+	NotModified  bool
+	ETag         string
+	LastModified string
+}
+
+// scrapeSite fetches a site and applies its extraction rules. Pages that are
+// unchanged since the last scrape are not parsed again; the codes from the
+// last parse are reported instead.
+func (b *Bot) scrapeSite(ctx context.Context, site ScrapeSite) ScrapeResult {
+	result := ScrapeResult{SiteName: site.Name, Status: ScrapeStatusError}
+
+	if _, err := NewCodeSource(site); err != nil {
+		result.Error = err
+		return result
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	state := b.loadScrapeState(site)
+	fetched, err := b.fetchSite(ctx, site, state)
 	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
+		result.Error = err
+		return result
+	}
+
+	now := time.Now()
+	state.CheckedAt = now
+	if fetched.ETag != "" || fetched.LastModified != "" {
+		state.ETag = fetched.ETag
+		state.LastModified = fetched.LastModified
+	}
+
+	contentHash := ""
+	if !fetched.NotModified {
+		contentHash = hashBytes(fetched.Body)
+	}
+	if fetched.NotModified || (state.ContentHash != "" && contentHash == state.ContentHash) {
+		codes, err := state.cachedCodes()
+		if err == nil {
+			b.saveScrapeState(state)
+			result.Status = ScrapeStatusUnchanged
+			result.Codes = codes
+			result.Rejected = state.Rejected
+			return result
+		}
+		// The cached parse is unusable, so fall back to a full fetch
+		b.GetLogger().WithError(err).WithField("site", site.Name).Warn("Discarding cached scrape state")
+		if fetched.NotModified {
+			state = ScrapeSiteState{SiteName: site.Name, ConfigHash: state.ConfigHash}
+			if fetched, err = b.fetchSite(ctx, site, state); err != nil {
+				result.Error = err
+				return result
+			}
+			contentHash = hashBytes(fetched.Body)
+		}
 	}
-	defer resp.Body.Close()
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	codes, rejected, err := b.extractCodes(site, fetched.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing HTML: %w", err)
+		result.Error = err
+		return result
+	}
+	if b.Config.Scrape.Fixtures.Record {
+		if err := b.saveFixture(site, fetched.Body, codes); err != nil {
+			b.GetLogger().WithError(err).WithField("site", site.Name).Error("Error recording scrape fixture")
+		}
 	}
 
-	var codes []GiftCode
-	doc.Find(site.Selector).Each(func(i int, s *goquery.Selection) {
-		code := strings.TrimSpace(s.Text())
-		description := strings.TrimSpace(s.Parent().Text())
-		description = strings.TrimPrefix(description, code)
-		description = strings.TrimSpace(description)
-		codes = append(codes, GiftCode{Code: code, Description: description, Source: site.Name})
-	})
+	state.ContentHash = contentHash
+	state.ChangedAt = now
+	state.Rejected = rejected
+	if err := state.setCodes(codes); err != nil {
+		b.GetLogger().WithError(err).WithField("site", site.Name).Error("Error encoding scraped codes")
+	}
+	b.saveScrapeState(state)
 
-	return codes, nil
+	result.Status = ScrapeStatusChanged
+	result.Codes = codes
+	result.Rejected = rejected
+	return result
 }
 
-func (b *Bot) findNewCodes(results []ScrapeResult) []GiftCode {
-	var newCodes []GiftCode
-	for _, result := range results {
-		for _, code := range result.Codes {
-			if !b.codeExists(code) {
-				newCodes = append(newCodes, code)
-			}
-		}
+// extractCodes runs a site's code source over a fetched document and filters
+// the candidates with the site's extraction rules.
+func (b *Bot) extractCodes(site ScrapeSite, body []byte) ([]GiftCode, int, error) {
+	source, err := NewCodeSource(site)
+	if err != nil {
+		return nil, 0, err
+	}
+	candidates, err := source.Extract(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	return b.filterCodes(site, candidates)
+}
+
+// fetchSite downloads a site, or reads its recorded fixture in replay mode.
+func (b *Bot) fetchSite(ctx context.Context, site ScrapeSite, state ScrapeSiteState) (fetchResult, error) {
+	if b.Config.Scrape.Fixtures.Replay {
+		return b.readFixture(site)
 	}
-	return newCodes
+	return b.fetchNetwork(ctx, site, state)
 }
 
-func (b *Bot) codeExists(code GiftCode) bool {
-	for _, existingCode := range b.lastCheckedCodes {
-		if existingCode.Code == code.Code {
-			return true
+// fetchNetwork downloads a site, retrying network errors, 429s and 5xx responses
+// according to the site's retry policy. Stored validators are sent so that
+// the server can answer 304 Not Modified.
+func (b *Bot) fetchNetwork(ctx context.Context, site ScrapeSite, state ScrapeSiteState) (fetchResult, error) {
+	retries := b.Config.Scrape.Retries
+	if site.Retries != nil {
+		retries = *site.Retries
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			b.GetLogger().WithError(lastErr).WithField("site", site.Name).Warnf("Retrying scrape (attempt %d of %d)", attempt, retries)
+			select {
+			case <-time.After(b.Config.Scrape.RetryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				return fetchResult{}, ctx.Err()
+			}
+		}
+
+		fetched, retryable, err := b.fetchOnce(ctx, site, state)
+		if err == nil {
+			return fetched, nil
+		}
+		lastErr = err
+		if !retryable {
+			break
 		}
 	}
-	return false
+	return fetchResult{}, lastErr
 }
 
-func (b *Bot) notifyNewCodes(ctx context.Context, newCodes []GiftCode) error {
-	message := "🎉 New gift codes found:\n\n"
-	for _, code := range newCodes {
-		message += fmt.Sprintf("**Code:** %s\n**Description:** %s\n**Source:** %s\n\n", code.Code, code.Description, code.Source)
+// fetchOnce performs a single request with the site's timeout. It reports
+// whether a failure is worth retrying.
+func (b *Bot) fetchOnce(ctx context.Context, site ScrapeSite, state ScrapeSiteState) (fetchResult, bool, error) {
+	timeout := b.Config.Scrape.Timeout
+	if site.Timeout > 0 {
+		timeout = site.Timeout
 	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	channelID := b.Config.Discord.NotificationChannelID
-	_, err := b.Session.ChannelMessageSend(channelID, message)
+	req, err := http.NewRequestWithContext(ctx, "GET", site.URL, nil)
 	if err != nil {
-		return fmt.Errorf("error sending new codes notification: %w", err)
+		return fetchResult{}, false, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("User-Agent", b.Config.Scrape.UserAgent)
+	if state.ETag != "" {
+		req.Header.Set("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		req.Header.Set("If-Modified-Since", state.LastModified)
 	}
-	b.GetLogger().WithField("code_count", len(newCodes)).Info("New gift codes notification sent")
-	return nil
-}
 
-func (b *Bot) StartPeriodicScraping() {
-	go func() {
-		ticker := time.NewTicker(1 * time.Hour) // Adjust the interval as needed
-		defer ticker.Stop()
+	resp, err := b.scrapeClient.Do(req)
+	if err != nil {
+		return fetchResult{}, true, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
 
-		for {
-			select {
-			case <-ticker.C:
-				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-				results, err := b.ScrapeGiftCodes(ctx)
-				if err != nil {
-					b.GetLogger().WithError(err).Error("Error during periodic scraping")
-				} else {
-					b.GetLogger().WithField("results", results).Info("Periodic scraping completed")
-				}
-				cancel()
-			case <-b.ctx.Done():
-				b.GetLogger().Info("Stopping periodic scraping")
-				return
-			}
+	fetched := fetchResult{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		fetched.NotModified = true
+		return fetched, false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return fetchResult{}, retryable, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	fetched.Body, err = io.ReadAll(io.LimitReader(resp.Body, maxScrapeBodySize))
+	if err != nil {
+		return fetchResult{}, true, fmt.Errorf("error reading response: %w", err)
+	}
+	return fetched, false, nil
+}
+
+// FindScrapeSite looks up a configured site by name, ignoring case.
+func (b *Bot) FindScrapeSite(name string) (ScrapeSite, bool) {
+	for _, site := range b.Config.Scrape.Sites {
+		if strings.EqualFold(site.Name, name) {
+			return site, true
 		}
-	}()
+	}
+	return ScrapeSite{}, false
 }
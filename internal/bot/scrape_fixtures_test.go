@@ -0,0 +1,162 @@
+// File: internal/bot/scrape_fixtures_test.go
+
+package bot
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// loadFixtureBot builds a bot from configs/config.yaml without a database
+// or Discord session, for running extraction against recorded fixtures.
+// Record fixtures with `!scrape record` or the --record-fixtures flag.
+func loadFixtureBot(t *testing.T) *Bot {
+	t.Helper()
+	const repoRoot = "../.."
+	config, err := LoadConfigFile(filepath.Join(repoRoot, "configs", "config.yaml"))
+	if err != nil {
+		t.Fatalf("error loading config: %v", err)
+	}
+	// The fixtures directory is relative to the repository root
+	if !filepath.IsAbs(config.Scrape.Fixtures.Dir) {
+		config.Scrape.Fixtures.Dir = filepath.Join(repoRoot, config.Scrape.Fixtures.Dir)
+	}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	return &Bot{Config: config, logger: logger}
+}
+
+func TestScrapeFixtures(t *testing.T) {
+	b := loadFixtureBot(t)
+	if len(b.Config.Scrape.Sites) == 0 {
+		t.Skip("no scrape sites configured")
+	}
+
+	for _, site := range b.Config.Scrape.Sites {
+		t.Run(site.Name, func(t *testing.T) {
+			expected, err := b.LoadExpectedCodes(site)
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("no fixture recorded for %s (%s)", site.Name, FixtureName(site))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			codes, err := b.ExtractFixture(site)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, len(codes))
+			for i, code := range codes {
+				got[i] = code.Code
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("codes extracted from %s = %q, want %q", FixtureName(site), got, expected)
+			}
+		})
+	}
+}
+
+// openTestDB opens a migrated in-memory database.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormlogger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := RunMigrations(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// newTestBot returns a bot with an empty config and a migrated in-memory
+// database, for testing the parts of the bot that don't need Discord.
+func newTestBot(t *testing.T) *Bot {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetLevel(logrus.WarnLevel)
+	return &Bot{Config: &Config{}, DB: openTestDB(t), logger: logger}
+}
+
+func TestScrapeReplay(t *testing.T) {
+	b := loadFixtureBot(t)
+	b.DB = openTestDB(t)
+	b.Config.Scrape.Fixtures.Replay = true
+
+	for _, site := range b.Config.Scrape.Sites {
+		t.Run(site.Name, func(t *testing.T) {
+			expected, err := b.LoadExpectedCodes(site)
+			if errors.Is(err, fs.ErrNotExist) {
+				t.Skipf("no fixture recorded for %s (%s)", site.Name, FixtureName(site))
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The second run must serve the codes from the unchanged content hash
+			for _, status := range []string{ScrapeStatusChanged, ScrapeStatusUnchanged} {
+				result := b.scrapeSite(context.Background(), site)
+				if result.Error != nil {
+					t.Fatal(result.Error)
+				}
+				if result.Status != status {
+					t.Errorf("status = %s, want %s", result.Status, status)
+				}
+				if len(result.Codes) != len(expected) {
+					t.Errorf("%s run found %d codes, want %d", status, len(result.Codes), len(expected))
+				}
+			}
+		})
+	}
+}
+
+func TestRecordFixtures(t *testing.T) {
+	b := loadFixtureBot(t)
+	site := ScrapeSite{Name: "Recorded Site", Type: SourceTypeHTML, Selector: "li strong"}
+	body := []byte(`<ul><li><strong>RECORD42</strong> Gems</li><li><strong>no</strong></li></ul>`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+	defer server.Close()
+	site.URL = server.URL
+	b.scrapeClient = server.Client()
+	b.Config.Scrape.Fixtures.Dir = t.TempDir()
+	b.DB = openTestDB(t)
+
+	results := b.RecordFixtures(context.Background(), []ScrapeSite{site}, Actor{ID: "1", GuildID: "2"})
+	if results[0].Error != nil {
+		t.Fatal(results[0].Error)
+	}
+	if events, err := b.ListAuditEvents("2", "1", "scrape.record"); err != nil || len(events) != 1 {
+		t.Errorf("audit events = %v, %v, want one scrape.record event", events, err)
+	}
+
+	recorded, err := os.ReadFile(filepath.Join(b.Config.Scrape.Fixtures.Dir, "recorded-site.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(recorded) != string(body) {
+		t.Errorf("recorded fixture = %q, want %q", recorded, body)
+	}
+	expected, err := b.LoadExpectedCodes(site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, []string{"RECORD42"}) {
+		t.Errorf("expected codes = %q, want [RECORD42]", expected)
+	}
+}
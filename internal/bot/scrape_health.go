@@ -0,0 +1,115 @@
+// File: internal/bot/scrape_health.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// minHealthRuns is how many successful runs a site needs before its
+// baseline is trusted for zero-match alerts.
+const minHealthRuns = 3
+
+// updateScrapeHealth folds the results of one scrape run into each site's
+// health record and alerts the admin channel when a site breaks or recovers.
+func (b *Bot) updateScrapeHealth(results []ScrapeResult) {
+	for _, result := range results {
+		health := b.loadScrapeHealth(result.SiteName)
+		if alert := b.applyScrapeResult(&health, result, time.Now()); alert != "" {
+			b.alertAdmins(alert)
+			// Alerts are only raised on transitions, so alerting now means the site just broke
+			if health.Alerting {
+				b.Notify(Notification{
+					Event: EventScraperBroken,
+					Data:  ScraperAlert{Site: health.SiteName, Message: alert, Error: health.LastError},
+				})
+			}
+		}
+		if err := b.DB.Save(&health).Error; err != nil {
+			b.GetLogger().WithError(err).WithField("site", result.SiteName).Error("Error saving scrape health")
+		}
+	}
+}
+
+// applyScrapeResult updates a health record with one result and returns the
+// alert message to send, if any.
+func (b *Bot) applyScrapeResult(health *ScrapeSiteHealth, result ScrapeResult, now time.Time) string {
+	cfg := b.Config.Scrape.Health
+	health.Runs++
+
+	if result.Error != nil {
+		health.ConsecutiveFailures++
+		health.LastError = result.Error.Error()
+		health.LastErrorAt = now
+		if health.ConsecutiveFailures == cfg.FailureThreshold && !health.Alerting {
+			health.Alerting = true
+			return fmt.Sprintf("🚨 Scraper **%s** has failed %d times in a row: %s",
+				health.SiteName, health.ConsecutiveFailures, health.LastError)
+		}
+		return ""
+	}
+
+	wasAlerting := health.Alerting
+	count := len(result.Codes)
+	health.ConsecutiveFailures = 0
+	health.LastSuccessAt = now
+	health.LastCodeCount = count
+	health.SuccessfulRuns++
+
+	var alert string
+	switch {
+	case count == 0 && health.SuccessfulRuns > minHealthRuns && health.BaselineCodes >= cfg.MinBaseline:
+		if !wasAlerting {
+			health.Alerting = true
+			alert = fmt.Sprintf("🚨 Scraper **%s** returned no codes but usually finds about %.1f. The selector may be broken.",
+				health.SiteName, health.BaselineCodes)
+		}
+	case wasAlerting:
+		health.Alerting = false
+		alert = fmt.Sprintf("✓ Scraper **%s** has recovered and returned %d codes.", health.SiteName, count)
+	}
+
+	// Exponential moving average over roughly the configured window of runs
+	if health.SuccessfulRuns == 1 {
+		health.BaselineCodes = float64(count)
+	} else {
+		alpha := 2 / float64(cfg.BaselineWindow+1)
+		health.BaselineCodes = alpha*float64(count) + (1-alpha)*health.BaselineCodes
+	}
+	return alert
+}
+
+func (b *Bot) loadScrapeHealth(siteName string) ScrapeSiteHealth {
+	var health ScrapeSiteHealth
+	err := b.DB.Where("site_name = ?", siteName).First(&health).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			b.GetLogger().WithError(err).WithField("site", siteName).Error("Error loading scrape health")
+		}
+		return ScrapeSiteHealth{SiteName: siteName}
+	}
+	return health
+}
+
+// ListScrapeHealth returns the health records of the configured sites in config order.
+func (b *Bot) ListScrapeHealth() []ScrapeSiteHealth {
+	records := make([]ScrapeSiteHealth, 0, len(b.Config.Scrape.Sites))
+	for _, site := range b.Config.Scrape.Sites {
+		records = append(records, b.loadScrapeHealth(site.Name))
+	}
+	return records
+}
+
+// alertAdmins posts an operational alert to the admin channel, if one is configured.
+func (b *Bot) alertAdmins(message string) {
+	b.GetLogger().Warn(message)
+	channelID := b.Config.Discord.AdminChannelID
+	if channelID == "" || b.Session == nil {
+		return
+	}
+	b.SendMessage(b.Session, channelID, message)
+}
@@ -0,0 +1,78 @@
+// File: internal/bot/scrape_state.go
+
+package bot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Scrape result statuses shown in the scrape report
+const (
+	ScrapeStatusChanged   = "changed"
+	ScrapeStatusUnchanged = "unchanged"
+	ScrapeStatusError     = "error"
+)
+
+// loadScrapeState returns the stored fetch state of a site. When the site's
+// configuration changed since the state was written, the validators and
+// cached parse are discarded so the page is fetched and parsed again.
+func (b *Bot) loadScrapeState(site ScrapeSite) ScrapeSiteState {
+	configHash := siteConfigHash(site)
+
+	var state ScrapeSiteState
+	err := b.DB.Where("site_name = ?", site.Name).First(&state).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		b.GetLogger().WithError(err).WithField("site", site.Name).Error("Error loading scrape state")
+	}
+	if err != nil || state.ConfigHash != configHash {
+		return ScrapeSiteState{SiteName: site.Name, ConfigHash: configHash}
+	}
+	return state
+}
+
+// siteConfigHash identifies a site's configuration. It is hashed as JSON so
+// pointer fields such as Retries contribute their value, not their address.
+func siteConfigHash(site ScrapeSite) string {
+	data, err := json.Marshal(site)
+	if err != nil {
+		return ""
+	}
+	return hashBytes(data)
+}
+
+func (b *Bot) saveScrapeState(state ScrapeSiteState) {
+	if err := b.DB.Save(&state).Error; err != nil {
+		b.GetLogger().WithError(err).WithField("site", state.SiteName).Error("Error saving scrape state")
+	}
+}
+
+func (s ScrapeSiteState) cachedCodes() ([]GiftCode, error) {
+	if s.Codes == "" {
+		return nil, errors.New("no cached codes")
+	}
+	var codes []GiftCode
+	err := json.Unmarshal([]byte(s.Codes), &codes)
+	return codes, err
+}
+
+func (s *ScrapeSiteState) setCodes(codes []GiftCode) error {
+	if codes == nil {
+		codes = []GiftCode{}
+	}
+	data, err := json.Marshal(codes)
+	if err != nil {
+		return err
+	}
+	s.Codes = string(data)
+	return nil
+}
+
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,68 @@
+// File: internal/bot/settings.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// GetGuildSettings returns the stored settings for a guild, falling back to
+// the configured defaults when the guild has not changed anything yet.
+func (b *Bot) GetGuildSettings(guildID string) GuildSettings {
+	settings := b.defaultGuildSettings(guildID)
+	if guildID == "" {
+		return settings
+	}
+	err := b.DB.Where("guild_id = ?", guildID).First(&settings).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		b.GetLogger().WithError(err).Errorf("Error loading settings for guild %s", guildID)
+	}
+	return settings
+}
+
+// SaveGuildSettings stores the settings for a guild.
+func (b *Bot) SaveGuildSettings(settings GuildSettings) error {
+	return b.DB.Save(&settings).Error
+}
+
+// SetGuildSetting turns a setting of the actor's guild on or off. name is
+// "suggestions" or "autoreplies".
+func (b *Bot) SetGuildSetting(name string, enabled bool, actor Actor) error {
+	settings := b.GetGuildSettings(actor.GuildID)
+	var setting *bool
+	switch name {
+	case "suggestions":
+		setting = &settings.SuggestionsEnabled
+	case "autoreplies":
+		setting = &settings.AutoRepliesEnabled
+	default:
+		return fmt.Errorf("unknown setting '%s'", name)
+	}
+	before := *setting
+	*setting = enabled
+
+	if err := b.SaveGuildSettings(settings); err != nil {
+		return fmt.Errorf("error saving settings: %w", err)
+	}
+	b.audit(actor, "settings.update", name, OnOff(before), OnOff(enabled))
+	return nil
+}
+
+// OnOff renders a setting as "on" or "off".
+func OnOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+func (b *Bot) defaultGuildSettings(guildID string) GuildSettings {
+	return GuildSettings{
+		GuildID:            guildID,
+		SuggestionsEnabled: b.Config.Suggestions.Enabled,
+		AutoRepliesEnabled: true,
+	}
+}
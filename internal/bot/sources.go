@@ -0,0 +1,258 @@
+// File: internal/bot/sources.go
+
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Scrape source types selectable with `type:` on a scrape site
+const (
+	SourceTypeHTML  = "html"
+	SourceTypeJSON  = "json"
+	SourceTypeRSS   = "rss"
+	SourceTypeAtom  = "atom"
+	SourceTypeRegex = "regex"
+)
+
+// CodeSource extracts candidate gift codes from a fetched document
+type CodeSource interface {
+	Extract(body []byte) ([]GiftCode, error)
+}
+
+// NewCodeSource returns the code source configured by the site's type.
+// Sites without a type use the HTML selector source.
+func NewCodeSource(site ScrapeSite) (CodeSource, error) {
+	switch strings.ToLower(site.Type) {
+	case "", SourceTypeHTML:
+		if site.Selector == "" {
+			return nil, fmt.Errorf("site %s: html source requires a selector", site.Name)
+		}
+		return &htmlSource{site: site}, nil
+	case SourceTypeJSON:
+		if site.Path == "" {
+			return nil, fmt.Errorf("site %s: json source requires a path", site.Name)
+		}
+		return &jsonSource{site: site}, nil
+	case SourceTypeRSS, SourceTypeAtom, "feed":
+		pattern, err := compileSourcePattern(site)
+		if err != nil {
+			return nil, err
+		}
+		return &feedSource{site: site, pattern: pattern}, nil
+	case SourceTypeRegex:
+		pattern, err := compileSourcePattern(site)
+		if err != nil {
+			return nil, err
+		}
+		return &regexSource{site: site, pattern: pattern}, nil
+	default:
+		return nil, fmt.Errorf("site %s: unknown source type '%s'", site.Name, site.Type)
+	}
+}
+
+func compileSourcePattern(site ScrapeSite) (*regexp.Regexp, error) {
+	if site.Pattern == "" {
+		return nil, fmt.Errorf("site %s: %s source requires a pattern", site.Name, site.Type)
+	}
+	pattern, err := regexp.Compile(site.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("site %s: invalid pattern: %w", site.Name, err)
+	}
+	return pattern, nil
+}
+
+// htmlSource runs a CSS selector over an HTML page and takes each match's
+// text, or the value of an attribute when one is configured.
+type htmlSource struct {
+	site ScrapeSite
+}
+
+func (h *htmlSource) Extract(body []byte) ([]GiftCode, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	var codes []GiftCode
+	doc.Find(h.site.Selector).Each(func(i int, s *goquery.Selection) {
+		code := strings.TrimSpace(s.Text())
+		if h.site.Attribute != "" {
+			code = strings.TrimSpace(s.AttrOr(h.site.Attribute, ""))
+		}
+		var description string
+		if h.site.DescriptionSelector != "" {
+			description = strings.TrimSpace(s.Parent().Find(h.site.DescriptionSelector).First().Text())
+		} else {
+			description = strings.TrimSpace(s.Parent().Text())
+			description = strings.TrimPrefix(description, code)
+			description = strings.TrimSpace(description)
+		}
+		codes = append(codes, GiftCode{Code: code, Description: description, Source: h.site.Name})
+	})
+	return codes, nil
+}
+
+// jsonSource walks a dotted path such as "data.codes[]" to a list of items and
+// reads the code (and optionally the description) from each item.
+type jsonSource struct {
+	site ScrapeSite
+}
+
+func (j *jsonSource) Extract(body []byte) ([]GiftCode, error) {
+	var root interface{}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	var codes []GiftCode
+	for _, item := range jsonPathValues(root, j.site.Path) {
+		code := jsonString(item, j.site.CodeKey)
+		if code == "" {
+			continue
+		}
+		codes = append(codes, GiftCode{
+			Code:        code,
+			Description: jsonString(item, j.site.DescriptionKey),
+			Source:      j.site.Name,
+		})
+	}
+	return codes, nil
+}
+
+// jsonPathValues resolves a dotted path against decoded JSON. A segment ending
+// in "[]" fans out over the elements of an array.
+func jsonPathValues(node interface{}, path string) []interface{} {
+	nodes := []interface{}{node}
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+		key, fanOut := strings.CutSuffix(segment, "[]")
+		var next []interface{}
+		for _, n := range nodes {
+			value := n
+			if key != "" {
+				object, ok := n.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if value, ok = object[key]; !ok {
+					continue
+				}
+			}
+			if fanOut {
+				if array, ok := value.([]interface{}); ok {
+					next = append(next, array...)
+				}
+				continue
+			}
+			next = append(next, value)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// jsonString reads key from an object, or the node itself when key is empty.
+func jsonString(node interface{}, key string) string {
+	if key != "" {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		node = object[key]
+	}
+	switch v := node.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	}
+	return ""
+}
+
+// feedSource reads RSS or Atom items and extracts codes from each item's
+// title and body with the site's pattern. The item title becomes the description.
+type feedSource struct {
+	site    ScrapeSite
+	pattern *regexp.Regexp
+}
+
+type feedDocument struct {
+	Items   []feedItem `xml:"channel>item"`
+	Entries []feedItem `xml:"entry"`
+}
+
+type feedItem struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Summary     string `xml:"summary"`
+	Content     string `xml:"content"`
+}
+
+func (f *feedSource) Extract(body []byte) ([]GiftCode, error) {
+	var feed feedDocument
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return nil, fmt.Errorf("error parsing feed: %w", err)
+	}
+
+	var codes []GiftCode
+	for _, item := range append(feed.Items, feed.Entries...) {
+		text := strings.Join([]string{item.Title, item.Description, item.Summary, item.Content}, "\n")
+		for _, code := range matchCodes(f.pattern, htmlToText(text)) {
+			codes = append(codes, GiftCode{Code: code, Description: strings.TrimSpace(item.Title), Source: f.site.Name})
+		}
+	}
+	return codes, nil
+}
+
+// regexSource runs the site's pattern over the whole response body.
+type regexSource struct {
+	site    ScrapeSite
+	pattern *regexp.Regexp
+}
+
+func (r *regexSource) Extract(body []byte) ([]GiftCode, error) {
+	var codes []GiftCode
+	for _, code := range matchCodes(r.pattern, string(body)) {
+		codes = append(codes, GiftCode{Code: code, Source: r.site.Name})
+	}
+	return codes, nil
+}
+
+// matchCodes returns the distinct matches of pattern in text, using the first
+// capture group when the pattern has one.
+func matchCodes(pattern *regexp.Regexp, text string) []string {
+	seen := make(map[string]bool)
+	var codes []string
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		code := match[0]
+		if len(match) > 1 {
+			code = match[1]
+		}
+		code = strings.TrimSpace(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes
+}
+
+// htmlToText strips markup from feed content, which is often escaped HTML.
+func htmlToText(content string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return content
+	}
+	return doc.Text()
+}
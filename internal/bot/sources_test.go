@@ -0,0 +1,142 @@
+// File: internal/bot/sources_test.go
+
+package bot
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPathValues(t *testing.T) {
+	const document = `{
+		"data": {
+			"codes": [
+				{"code": "ALPHA", "rewards": ["gems", "food"]},
+				{"code": "BETA"},
+				"loose"
+			],
+			"featured": {"code": "GAMMA"},
+			"count": 3
+		}
+	}`
+	var root interface{}
+	if err := json.Unmarshal([]byte(document), &root); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		want []interface{}
+	}{
+		{"object", "data.featured.code", []interface{}{"GAMMA"}},
+		{"number", "data.count", []interface{}{float64(3)}},
+		{"fan out", "data.codes[].code", []interface{}{"ALPHA", "BETA"}},
+		{"nested fan out", "data.codes[].rewards[]", []interface{}{"gems", "food"}},
+		{"empty segments", ".data..featured.code", []interface{}{"GAMMA"}},
+		{"missing key", "data.missing", nil},
+		{"fan out over an object", "data.featured[]", nil},
+		{"key on an array", "data.codes.code", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonPathValues(root, tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jsonPathValues(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+
+	if got := jsonPathValues(root, ""); len(got) != 1 || !reflect.DeepEqual(got[0], root) {
+		t.Errorf("empty path = %v, want the root", got)
+	}
+}
+
+func TestSourceExtract(t *testing.T) {
+	tests := []struct {
+		name string
+		site ScrapeSite
+		body string
+		want []GiftCode
+	}{
+		{
+			"json",
+			ScrapeSite{Name: "api", Type: SourceTypeJSON, Path: "codes[]", CodeKey: "code", DescriptionKey: "reward"},
+			`{"codes": [{"code": " ALPHA ", "reward": "Gems"}, {"code": 1234}, {"reward": "no code"}]}`,
+			[]GiftCode{
+				{Code: "ALPHA", Description: "Gems", Source: "api"},
+				{Code: "1234", Source: "api"},
+			},
+		},
+		{
+			"rss",
+			ScrapeSite{Name: "feed", Type: SourceTypeRSS, Pattern: `code:\s*([A-Z0-9]+)`},
+			`<rss><channel>
+				<item><title>New code: ALPHA</title><description>Use code: ALPHA for gems</description></item>
+				<item><title>Weekend</title><description>&lt;p&gt;code: &lt;b&gt;BETA&lt;/b&gt;&lt;/p&gt;</description></item>
+				<item><title>Patch notes</title><description>Nothing here</description></item>
+			</channel></rss>`,
+			[]GiftCode{
+				{Code: "ALPHA", Description: "New code: ALPHA", Source: "feed"},
+				{Code: "BETA", Description: "Weekend", Source: "feed"},
+			},
+		},
+		{
+			"atom",
+			ScrapeSite{Name: "feed", Type: SourceTypeAtom, Pattern: `\bGC-[A-Z0-9]+\b`},
+			`<feed xmlns="http://www.w3.org/2005/Atom">
+				<entry><title>Gift</title><summary>Redeem GC-ONE or GC-TWO</summary></entry>
+				<entry><title>Again</title><content type="html">GC-ONE</content></entry>
+			</feed>`,
+			[]GiftCode{
+				{Code: "GC-ONE", Description: "Gift", Source: "feed"},
+				{Code: "GC-TWO", Description: "Gift", Source: "feed"},
+				{Code: "GC-ONE", Description: "Again", Source: "feed"},
+			},
+		},
+		{
+			"regex",
+			ScrapeSite{Name: "page", Type: SourceTypeRegex, Pattern: `code=([A-Z]+)`},
+			`code=ALPHA code=BETA code=ALPHA`,
+			[]GiftCode{
+				{Code: "ALPHA", Source: "page"},
+				{Code: "BETA", Source: "page"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := NewCodeSource(tt.site)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := source.Extract([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Extract = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCodeSourceErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		site ScrapeSite
+	}{
+		{"html without selector", ScrapeSite{Name: "s"}},
+		{"json without path", ScrapeSite{Name: "s", Type: SourceTypeJSON}},
+		{"rss without pattern", ScrapeSite{Name: "s", Type: SourceTypeRSS}},
+		{"bad pattern", ScrapeSite{Name: "s", Type: SourceTypeRegex, Pattern: "("}},
+		{"unknown type", ScrapeSite{Name: "s", Type: "ftp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCodeSource(tt.site); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
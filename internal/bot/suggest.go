@@ -0,0 +1,168 @@
+// File: internal/bot/suggest.go
+
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// minSuggestInputLength is the shortest input we try to find suggestions for,
+// so that messages such as "!!" or "!?" are ignored.
+const minSuggestInputLength = 2
+
+type suggestion struct {
+	value    string
+	prefix   bool
+	distance int
+}
+
+// Suggest returns up to limit candidates that are close to input, either because
+// the candidate starts with the input or because its edit distance is at most
+// maxDistance. Prefix matches are ranked first, then by distance and name.
+func Suggest(input string, candidates []string, maxDistance, limit int) []string {
+	input = strings.ToLower(input)
+	length := utf8.RuneCountInString(input)
+	if length < minSuggestInputLength {
+		return nil
+	}
+	// Short inputs get a tighter threshold so that e.g. "ab" doesn't match everything
+	if maxDistance > length/2 {
+		maxDistance = length / 2
+	}
+
+	seen := make(map[string]bool)
+	var matches []suggestion
+	for _, candidate := range candidates {
+		lower := strings.ToLower(candidate)
+		if lower == input || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		prefix := strings.HasPrefix(lower, input)
+		distance := levenshtein(input, lower)
+		if prefix || distance <= maxDistance {
+			matches = append(matches, suggestion{value: candidate, prefix: prefix, distance: distance})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].prefix != matches[j].prefix {
+			return matches[i].prefix
+		}
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].value < matches[j].value
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	result := make([]string, len(matches))
+	for i, match := range matches {
+		result[i] = match.value
+	}
+	return result
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// SuggestCommands returns the names and aliases of the commands a member with
+// the given roles can run that are closest to input.
+func (b *Bot) SuggestCommands(roles []string, input string) []string {
+	var candidates []string
+	for _, cmd := range b.Commands() {
+		if cmd.Hidden || !b.CanRunWithRoles(roles, cmd) {
+			continue
+		}
+		candidates = append(candidates, cmd.Name)
+		candidates = append(candidates, cmd.Aliases...)
+	}
+	return Suggest(input, candidates, b.Config.Suggestions.MaxDistance, b.Config.Suggestions.MaxResults)
+}
+
+// SuggestSubcommands returns the names and aliases of the subcommands of cmd
+// a member with the given roles can run that are closest to input.
+func (b *Bot) SuggestSubcommands(roles []string, cmd *Command, input string) []string {
+	var candidates []string
+	for _, subCmd := range cmd.Subcommands {
+		if subCmd.Hidden || !b.CanRunWithRoles(roles, subCmd) {
+			continue
+		}
+		candidates = append(candidates, subCmd.Name)
+		candidates = append(candidates, subCmd.Aliases...)
+	}
+	return Suggest(input, candidates, b.Config.Suggestions.MaxDistance, b.Config.Suggestions.MaxResults)
+}
+
+// SuggestTerms returns the stored term titles closest to input.
+func (b *Bot) SuggestTerms(input string) []string {
+	var titles, aliases []string
+	if err := b.DB.Model(&Term{}).Pluck("term", &titles).Error; err != nil {
+		b.GetLogger().WithError(err).Error("Error loading terms for suggestions")
+		return nil
+	}
+	if err := b.DB.Model(&TermAlias{}).Pluck("alias", &aliases).Error; err != nil {
+		b.GetLogger().WithError(err).Error("Error loading term aliases for suggestions")
+		return nil
+	}
+	titles = append(titles, aliases...)
+	return Suggest(input, titles, b.Config.Suggestions.MaxDistance, b.Config.Suggestions.MaxResults)
+}
+
+// SuggestionsEnabled reports whether "did you mean" replies are turned on for a guild.
+func (b *Bot) SuggestionsEnabled(guildID string) bool {
+	return b.GetGuildSettings(guildID).SuggestionsEnabled
+}
+
+// FormatSuggestions renders a "Did you mean" line, wrapping each suggestion with
+// the given prefix. It returns an empty string when there are no suggestions.
+func FormatSuggestions(prefix string, suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		quoted[i] = fmt.Sprintf("`%s%s`", prefix, s)
+	}
+	return fmt.Sprintf("Did you mean %s?", strings.Join(quoted, ", "))
+}
+
+// suggestUnknownCommand replies with the closest commands when an unknown
+// command was used. It stays silent if there is nothing close enough.
+func (b *Bot) suggestUnknownCommand(s *discordgo.Session, m *discordgo.MessageCreate, name string) {
+	if !b.SuggestionsEnabled(m.GuildID) {
+		return
+	}
+	suggestions := b.SuggestCommands(b.MemberRoles(s, m.GuildID, m.Author.ID), name)
+	if len(suggestions) == 0 {
+		return
+	}
+	b.SendMessage(s, m.ChannelID, fmt.Sprintf("Unknown command `%s`. %s",
+		b.WithPrefix(name), FormatSuggestions(b.Config.Discord.CommandPrefix, suggestions)))
+}
@@ -0,0 +1,133 @@
+// File: internal/bot/suggest_test.go
+
+package bot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"term", "term", 0},
+		{"", "term", 4},
+		{"trem", "term", 2},
+		{"tern", "term", 1},
+		{"helpp", "help", 1},
+		{"kitten", "sitting", 3},
+		{"héllo", "hello", 1},
+		{"日本語", "日本", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"help", "term", "terms", "giftcode", "id", "Help", "scrape", "schedule", "zéd"}
+	tests := []struct {
+		name        string
+		input       string
+		maxDistance int
+		limit       int
+		want        []string
+	}{
+		{"typo", "hepl", 2, 3, []string{"help"}},
+		{"prefix first", "ter", 2, 3, []string{"term", "terms"}},
+		{"exact match left out", "term", 2, 3, []string{"terms"}},
+		{"limit", "sc", 2, 1, []string{"scrape"}},
+		{"too short", "h", 2, 3, nil},
+		{"no match", "zzzz", 2, 3, []string{}},
+		{"case insensitive", "GIFTCOD", 2, 3, []string{"giftcode"}},
+		{"accented", "térms", 2, 3, []string{"terms", "term"}},
+		// Three characters allow one edit, even though the input is four bytes
+		{"counts characters", "zàq", 2, 3, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.input, candidates, tt.maxDistance, tt.limit)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCommands(t *testing.T) {
+	b := &Bot{Config: &Config{}}
+	b.Config.Discord.RoleID = "admin-role"
+	b.Config.Suggestions.MaxDistance = 2
+	b.Config.Suggestions.MaxResults = 3
+	b.commands = map[string]*Command{
+		"help":   {Name: "help", Aliases: []string{"h"}},
+		"scrape": {Name: "scrape", Permission: "admin"},
+		"secret": {Name: "secret", Hidden: true},
+		"term": {Name: "term", Subcommands: map[string]*Command{
+			"add":    {Name: "add"},
+			"remove": {Name: "remove", Permission: "admin"},
+		}},
+	}
+
+	tests := []struct {
+		name  string
+		roles []string
+		input string
+		want  []string
+	}{
+		{"member", nil, "scrap", []string{}},
+		{"admin", []string{"admin-role"}, "scrap", []string{"scrape"}},
+		{"hidden", []string{"admin-role"}, "secre", []string{}},
+		{"typo", nil, "hlep", []string{"help"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.SuggestCommands(tt.roles, tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestCommands(%q, %q) = %q, want %q", tt.roles, tt.input, got, tt.want)
+			}
+		})
+	}
+
+	term := b.commands["term"]
+	if got := b.SuggestSubcommands(nil, term, "remov"); len(got) > 0 {
+		t.Errorf("members were offered an admin subcommand: %q", got)
+	}
+	if got, want := b.SuggestSubcommands([]string{"admin-role"}, term, "remov"), []string{"remove"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SuggestSubcommands = %q, want %q", got, want)
+	}
+}
+
+func TestSuggestTerms(t *testing.T) {
+	b := newTestBot(t)
+	b.Config.Suggestions.MaxDistance = 2
+	b.Config.Suggestions.MaxResults = 3
+	terms := []Term{
+		{Term: "Frost Star", Aliases: []TermAlias{{Alias: "FS"}}},
+		{Term: "Furnace"},
+		{Term: "Chief Gear"},
+	}
+	if err := b.DB.Create(&terms).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"furnce", []string{"Furnace"}},
+		{"frost", []string{"Frost Star"}},
+		{"chief gaer", []string{"Chief Gear"}},
+		{"fz", []string{"FS"}},
+		{"nothing", []string{}},
+	}
+	for _, tt := range tests {
+		if got := b.SuggestTerms(tt.input); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SuggestTerms(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+// File: internal/bot/term_history.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// recordTermRevision stores the current state of a term as its next revision.
+func recordTermRevision(tx *gorm.DB, termID uint, action, note, authorID string) error {
+	var t Term
+	if err := tx.Unscoped().Preload("Aliases").First(&t, termID).Error; err != nil {
+		return err
+	}
+	var last int
+	if err := tx.Model(&TermRevision{}).Where("term_id = ?", termID).
+		Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+		return err
+	}
+	return tx.Create(&TermRevision{
+		TermID:      termID,
+		Revision:    last + 1,
+		Action:      action,
+		Note:        note,
+		Term:        t.Term,
+		Description: t.Description,
+		Category:    t.Category,
+		Aliases:     t.AliasList(),
+		AuthorID:    authorID,
+	}).Error
+}
+
+// findTermWithHistory looks up a term by title or alias, falling back to
+// removed terms by title.
+func (b *Bot) findTermWithHistory(name string) (Term, error) {
+	t, err := b.FindTerm(name)
+	if errors.Is(err, ErrTermNotFound) {
+		if removed, removedErr := b.findRemovedTerm(name); removedErr == nil {
+			return removed, nil
+		}
+	}
+	return t, err
+}
+
+// findRemovedTerm returns the most recently removed term with a title.
+func (b *Bot) findRemovedTerm(name string) (Term, error) {
+	var t Term
+	result := b.DB.Unscoped().Where("deleted_at IS NOT NULL AND LOWER(term) = ?", strings.ToLower(NormalizeTermTitle(name))).
+		Order("deleted_at DESC").First(&t)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return t, fmt.Errorf("%w: no removed term '%s'", ErrTermNotFound, NormalizeTermTitle(name))
+	}
+	return t, result.Error
+}
+
+// TermHistory returns the revisions of a term, newest first. Removed terms
+// keep their history.
+func (b *Bot) TermHistory(name string) (Term, []TermRevision, error) {
+	t, err := b.findTermWithHistory(name)
+	if err != nil {
+		return t, nil, err
+	}
+	var revisions []TermRevision
+	if err := b.DB.Where("term_id = ?", t.ID).Order("revision DESC").Find(&revisions).Error; err != nil {
+		return t, nil, fmt.Errorf("error loading term history: %w", err)
+	}
+	return t, revisions, nil
+}
+
+// TermRevisionDiff returns a revision of a term and the revision before it.
+// The previous revision is empty for the first revision.
+func (b *Bot) TermRevisionDiff(name string, revision int) (TermRevision, TermRevision, error) {
+	var previous TermRevision
+	current, err := b.termRevision(name, revision)
+	if err != nil {
+		return current, previous, err
+	}
+	if revision > 1 {
+		if err := b.DB.Where("term_id = ? AND revision = ?", current.TermID, revision-1).First(&previous).Error; err != nil {
+			return current, previous, fmt.Errorf("error loading revision %d: %w", revision-1, err)
+		}
+	}
+	return current, previous, nil
+}
+
+func (b *Bot) termRevision(name string, revision int) (TermRevision, error) {
+	var rev TermRevision
+	t, err := b.findTermWithHistory(name)
+	if err != nil {
+		return rev, err
+	}
+	result := b.DB.Where("term_id = ? AND revision = ?", t.ID, revision).First(&rev)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return rev, fmt.Errorf("term '%s' has no revision %d", t.Term, revision)
+	}
+	return rev, result.Error
+}
+
+// RevertTerm sets the description, category and aliases of a term back to
+// those of an earlier revision, recording the revert as a new revision.
+// Aliases that another term has taken since are skipped, as in RestoreTerm.
+// It returns the revision and the aliases that were skipped.
+func (b *Bot) RevertTerm(name string, revision int, actor Actor) (TermRevision, []string, error) {
+	existingTerm, err := b.FindTerm(name)
+	if err != nil {
+		return TermRevision{}, nil, err
+	}
+	rev, err := b.termRevision(existingTerm.Term, revision)
+	if err != nil {
+		return rev, nil, err
+	}
+
+	var aliases, skipped []string
+	for _, alias := range rev.Aliases {
+		if owner, err := b.FindTerm(alias); err == nil && owner.ID == existingTerm.ID {
+			aliases = append(aliases, alias)
+		} else if err := b.checkTermNameFree(alias); err != nil {
+			skipped = append(skipped, alias)
+		} else {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	oldDescription := existingTerm.Description
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&existingTerm).Updates(map[string]interface{}{
+			"description": rev.Description,
+			"category":    rev.Category,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("term_id = ?", existingTerm.ID).Delete(&TermAlias{}).Error; err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			if err := tx.Create(&TermAlias{TermID: existingTerm.ID, Alias: alias}).Error; err != nil {
+				return err
+			}
+		}
+		return recordTermRevision(tx, existingTerm.ID, TermActionRevert, fmt.Sprintf("reverted to #%d", revision), actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", existingTerm.Term).WithField("revision", revision).Error("Error reverting term")
+		return rev, nil, err
+	}
+	b.audit(actor, "term.revert", existingTerm.Term, oldDescription, rev.Description)
+	b.GetLogger().WithField("term", existingTerm.Term).WithField("revision", revision).Info("Term reverted")
+	return rev, skipped, nil
+}
+
+// RestoreTerm brings back a removed term together with the aliases it had
+// when it was removed, skipping aliases that another term has taken since.
+// It returns the restored term and the aliases that were skipped.
+func (b *Bot) RestoreTerm(name string, actor Actor) (Term, []string, error) {
+	removed, err := b.findRemovedTerm(name)
+	if err != nil {
+		return removed, nil, err
+	}
+	if err := b.checkTermNameFree(removed.Term); err != nil {
+		return removed, nil, err
+	}
+
+	var last TermRevision
+	if err := b.DB.Where("term_id = ?", removed.ID).Order("revision DESC").First(&last).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return removed, nil, fmt.Errorf("error loading term history: %w", err)
+	}
+
+	var aliases, skipped []string
+	for _, alias := range last.Aliases {
+		if err := b.checkTermNameFree(alias); err != nil {
+			skipped = append(skipped, alias)
+		} else {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&removed).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		for _, alias := range aliases {
+			if err := tx.Create(&TermAlias{TermID: removed.ID, Alias: alias}).Error; err != nil {
+				return err
+			}
+		}
+		return recordTermRevision(tx, removed.ID, TermActionRestore, "", actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", removed.Term).Error("Error restoring term")
+		return removed, nil, err
+	}
+	b.audit(actor, "term.restore", removed.Term, "", removed.Description)
+	b.GetLogger().WithField("term", removed.Term).Info("Term restored")
+	removed.DeletedAt = gorm.DeletedAt{}
+	return removed, skipped, nil
+}
+
+// Text renders a revision for diffing: its category and aliases, followed by
+// the description. Removed terms render as empty.
+func (r TermRevision) Text() string {
+	if r.Revision == 0 || r.Action == TermActionRemove {
+		return ""
+	}
+	category := r.Category
+	if category == "" {
+		category = UncategorizedTerms
+	}
+	return fmt.Sprintf("category: %s\naliases: %s\n\n%s", category, strings.Join(r.Aliases, ", "), r.Description)
+}
+
+// DiffLines returns a line diff turning before into after. Unchanged lines
+// start with two spaces, removed lines with "- " and added lines with "+ ".
+func DiffLines(before, after string) []string {
+	a, b := splitLines(before), splitLines(after)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
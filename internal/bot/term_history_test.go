@@ -0,0 +1,85 @@
+// File: internal/bot/term_history_test.go
+
+package bot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []string
+	}{
+		{"both empty", "", "", nil},
+		{"unchanged", "a\nb", "a\nb", []string{"  a", "  b"}},
+		{"added", "", "a\nb", []string{"+ a", "+ b"}},
+		{"removed", "a\nb", "", []string{"- a", "- b"}},
+		{"changed line", "a\nb\nc", "a\nx\nc", []string{"  a", "- b", "+ x", "  c"}},
+		{"inserted line", "a\nc", "a\nb\nc", []string{"  a", "+ b", "  c"}},
+		{"moved line", "a\nb\nc", "b\nc\na", []string{"- a", "  b", "  c", "+ a"}},
+		{"windows line endings", "a\r\nb", "a\nb", []string{"  a", "  b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffLines(%q, %q) = %q, want %q", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevertTermAliases(t *testing.T) {
+	b := newTestBot(t)
+	actor := Actor{ID: "editor"}
+
+	if err := b.AddTerm("Frost Star", "Currency.", actor); err != nil {
+		t.Fatal(err)
+	}
+	for _, alias := range []string{"FS", "Stars"} {
+		if _, err := b.AddTermAlias("Frost Star", alias, actor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Revision 3 has both aliases
+	for _, alias := range []string{"FS", "Stars"} {
+		if _, err := b.RemoveTermAlias(alias, actor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.AddTermAlias("Frost Star", "Frosty", actor); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddTerm("Stars", "Another term.", actor); err != nil {
+		t.Fatal(err)
+	}
+
+	_, skipped, err := b.RevertTerm("Frost Star", 3, actor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"Stars"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %q, want %q", skipped, want)
+	}
+	term, err := b.FindTerm("Frost Star")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := term.AliasList(), []string{"FS"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("aliases = %q, want %q", got, want)
+	}
+	if stars, err := b.FindTerm("Stars"); err != nil || stars.Term != "Stars" {
+		t.Errorf("FindTerm(Stars) = %q, %v, want the other term", stars.Term, err)
+	}
+
+	// Reverting again replaces the restored aliases with the revision's
+	if _, _, err := b.RevertTerm("Frost Star", 6, actor); err != nil {
+		t.Fatal(err)
+	}
+	term, _ = b.FindTerm("Frost Star")
+	if got, want := term.AliasList(), []string{"Frosty"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("aliases after reverting to #6 = %q, want %q", got, want)
+	}
+}
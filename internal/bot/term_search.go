@@ -0,0 +1,187 @@
+// File: internal/bot/term_search.go
+
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// termSearchSchema creates the FTS5 index over term titles, aliases and
+// descriptions. Its rowids are term IDs, and triggers keep it in sync with the
+// terms and term_aliases tables, including soft deletes.
+var termSearchSchema = []string{
+	`CREATE VIRTUAL TABLE IF NOT EXISTS terms_fts USING fts5(term, aliases, description, tokenize = 'unicode61 remove_diacritics 2')`,
+	`CREATE TRIGGER IF NOT EXISTS terms_fts_insert AFTER INSERT ON terms WHEN NEW.deleted_at IS NULL BEGIN
+		INSERT INTO terms_fts(rowid, term, aliases, description)
+		VALUES (NEW.id, NEW.term, (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = NEW.id), NEW.description);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS terms_fts_update AFTER UPDATE ON terms BEGIN
+		DELETE FROM terms_fts WHERE rowid = OLD.id;
+		INSERT INTO terms_fts(rowid, term, aliases, description)
+		SELECT NEW.id, NEW.term, (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = NEW.id), NEW.description
+		WHERE NEW.deleted_at IS NULL;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS terms_fts_delete AFTER DELETE ON terms BEGIN
+		DELETE FROM terms_fts WHERE rowid = OLD.id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS term_aliases_fts_insert AFTER INSERT ON term_aliases BEGIN
+		UPDATE terms_fts SET aliases = (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = NEW.term_id) WHERE rowid = NEW.term_id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS term_aliases_fts_update AFTER UPDATE ON term_aliases BEGIN
+		UPDATE terms_fts SET aliases = (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = OLD.term_id) WHERE rowid = OLD.term_id;
+		UPDATE terms_fts SET aliases = (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = NEW.term_id) WHERE rowid = NEW.term_id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS term_aliases_fts_delete AFTER DELETE ON term_aliases BEGIN
+		UPDATE terms_fts SET aliases = (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = OLD.term_id) WHERE rowid = OLD.term_id;
+	END`,
+}
+
+var termSearchTriggers = []string{
+	"terms_fts_insert", "terms_fts_update", "terms_fts_delete",
+	"term_aliases_fts_insert", "term_aliases_fts_update", "term_aliases_fts_delete",
+}
+
+// setupTermSearch creates the FTS5 term index and rebuilds it from the terms
+// table. SQLite builds without FTS5 (go-sqlite3 needs the sqlite_fts5 build
+// tag) fall back to slower LIKE searches, so it reports whether FTS5 is used.
+func setupTermSearch(db *gorm.DB, logger *logrus.Logger) bool {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range termSearchSchema {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM terms_fts").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO terms_fts(rowid, term, aliases, description)
+			SELECT id, term, (SELECT group_concat(alias, ' ') FROM term_aliases WHERE term_id = terms.id), description
+			FROM terms WHERE deleted_at IS NULL`).Error
+	})
+	if err == nil {
+		return true
+	}
+
+	logger.WithError(err).Warn("Full-text term search unavailable, falling back to LIKE searches")
+	// Triggers left behind by an FTS5 build would make every term write fail
+	for _, trigger := range termSearchTriggers {
+		if err := db.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			logger.WithError(err).WithField("trigger", trigger).Error("Error dropping term search trigger")
+		}
+	}
+	return false
+}
+
+// TermSearchResult is a term matching a search, with the matching words
+// highlighted in bold.
+type TermSearchResult struct {
+	Term    string
+	Snippet string
+	Rank    float64
+}
+
+// SearchTerms returns the terms whose title, aliases or description contain
+// every word of query (as a word prefix), best matches first.
+func (b *Bot) SearchTerms(query string, limit int) ([]TermSearchResult, error) {
+	words := strings.Fields(query)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("nothing to search for")
+	}
+	if b.termSearch {
+		return b.searchTermsFTS(words, limit)
+	}
+	return b.searchTermsLike(words, limit)
+}
+
+func (b *Bot) searchTermsFTS(words []string, limit int) ([]TermSearchResult, error) {
+	// Quote every word so that FTS5 operators typed by users are searched for literally
+	phrases := make([]string, len(words))
+	for i, word := range words {
+		phrases[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+
+	var results []TermSearchResult
+	err := b.DB.Raw(`SELECT highlight(terms_fts, 0, '**', '**') AS term,
+			snippet(terms_fts, 2, '**', '**', '…', 16) AS snippet,
+			bm25(terms_fts, 10.0, 5.0, 1.0) AS rank
+		FROM terms_fts WHERE terms_fts MATCH ? ORDER BY rank LIMIT ?`,
+		strings.Join(phrases, " "), limit).Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("error searching terms: %w", err)
+	}
+	return results, nil
+}
+
+// searchTermsLike is the search used without FTS5. Terms whose title matches
+// rank before terms matching on aliases or descriptions only.
+func (b *Bot) searchTermsLike(words []string, limit int) ([]TermSearchResult, error) {
+	query := b.DB.Model(&Term{})
+	for _, word := range words {
+		pattern := "%" + strings.ToLower(word) + "%"
+		query = query.Where(`LOWER(term) LIKE ? OR LOWER(description) LIKE ?
+			OR id IN (SELECT term_id FROM term_aliases WHERE LOWER(alias) LIKE ?)`, pattern, pattern, pattern)
+	}
+	var terms []Term
+	if err := query.Find(&terms).Error; err != nil {
+		return nil, fmt.Errorf("error searching terms: %w", err)
+	}
+
+	results := make([]TermSearchResult, 0, len(terms))
+	for _, t := range terms {
+		rank := 0.0
+		for _, word := range words {
+			if strings.Contains(strings.ToLower(t.Term), strings.ToLower(word)) {
+				rank--
+			}
+		}
+		results = append(results, TermSearchResult{
+			Term:    highlightWords(t.Term, words),
+			Snippet: highlightWords(Truncate(t.Description, 160), words),
+			Rank:    rank,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Rank < results[j].Rank })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// highlightWords wraps case-insensitive occurrences of words in bold.
+func highlightWords(text string, words []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text
+	}
+	marked := make([]bool, len(text))
+	for _, word := range words {
+		word = strings.ToLower(word)
+		for start := 0; word != ""; {
+			i := strings.Index(lower[start:], word)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(word); j++ {
+				marked[j] = true
+			}
+			start += i + len(word)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(text); i++ {
+		if marked[i] && (i == 0 || !marked[i-1]) {
+			sb.WriteString("**")
+		}
+		sb.WriteByte(text[i])
+		if marked[i] && (i == len(text)-1 || !marked[i+1]) {
+			sb.WriteString("**")
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,278 @@
+// File: internal/bot/term_suggestions.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// TermSuggestionComponent is the custom ID prefix of the review buttons posted
+// for suggested term changes. The buttons carry the action and the suggestion
+// ID as arguments.
+const TermSuggestionComponent = "termsuggestion"
+
+// Term suggestion review actions
+const (
+	TermSuggestionAccept = "accept"
+	TermSuggestionReject = "reject"
+)
+
+// IsGlossaryEditor reports whether a user may change any term: admins and
+// members with the glossary editor role.
+func (b *Bot) IsGlossaryEditor(s *discordgo.Session, guildID, userID string) bool {
+	if b.IsAdmin(s, guildID, userID) {
+		return true
+	}
+	roleID := b.Config.Glossary.EditorRoleID
+	return roleID != "" && b.HasRole(s, guildID, userID, roleID)
+}
+
+// CanEditTerm reports whether a user may change a term: glossary editors, and
+// the member who added it unless creators_can_edit is turned off.
+func (b *Bot) CanEditTerm(s *discordgo.Session, guildID, userID string, t Term) bool {
+	if b.Config.Glossary.CreatorsCanEdit && t.AuthorID != "" && t.AuthorID == userID {
+		return true
+	}
+	return b.IsGlossaryEditor(s, guildID, userID)
+}
+
+// CanReviewTermSuggestion reports whether a user may accept or reject a
+// suggestion. Besides glossary editors, members who may change a term can
+// review suggested edits to it.
+func (b *Bot) CanReviewTermSuggestion(s *discordgo.Session, guildID, userID string, suggestion TermSuggestion) bool {
+	if suggestion.Action != TermActionAdd {
+		if t, err := b.FindTerm(suggestion.Term); err == nil {
+			return b.CanEditTerm(s, guildID, userID, t)
+		}
+	}
+	return b.IsGlossaryEditor(s, guildID, userID)
+}
+
+// SuggestTermChange queues a change to the glossary for review. action is
+// TermActionAdd, TermActionEdit or TermActionRemove, and description is the
+// proposed description for additions and edits.
+func (b *Bot) SuggestTermChange(action, term, description, channelID string, actor Actor) (TermSuggestion, error) {
+	suggestion := TermSuggestion{
+		Action:      action,
+		Term:        NormalizeTermTitle(term),
+		Description: description,
+		SuggestedBy: actor.ID,
+		ChannelID:   channelID,
+		Status:      TermSuggestionPending,
+	}
+
+	switch action {
+	case TermActionAdd:
+		if err := b.checkTermNameFree(suggestion.Term); err != nil {
+			return suggestion, err
+		}
+	case TermActionEdit, TermActionRemove:
+		existing, err := b.FindTerm(suggestion.Term)
+		if err != nil {
+			return suggestion, err
+		}
+		suggestion.Term = existing.Term
+		suggestion.Previous = existing.Description
+	default:
+		return suggestion, fmt.Errorf("unknown term suggestion action '%s'", action)
+	}
+
+	var pending int64
+	err := b.DB.Model(&TermSuggestion{}).
+		Where("status = ? AND suggested_by = ? AND LOWER(term) = ?", TermSuggestionPending, actor.ID, strings.ToLower(suggestion.Term)).
+		Count(&pending).Error
+	if err != nil {
+		return suggestion, fmt.Errorf("error loading term suggestions: %w", err)
+	}
+	if pending > 0 {
+		return suggestion, fmt.Errorf("you already suggested a change to '%s' that is waiting for review", suggestion.Term)
+	}
+
+	if err := b.DB.Create(&suggestion).Error; err != nil {
+		return suggestion, fmt.Errorf("error saving term suggestion: %w", err)
+	}
+	b.audit(actor, "term.suggest", suggestion.Term, suggestion.Previous, suggestion.Description)
+	b.sendTermSuggestionReview(suggestion)
+	return suggestion, nil
+}
+
+// ListTermSuggestions returns the suggestions waiting for review, oldest first.
+func (b *Bot) ListTermSuggestions() ([]TermSuggestion, error) {
+	var suggestions []TermSuggestion
+	err := b.DB.Where("status = ?", TermSuggestionPending).Order("created_at").Find(&suggestions).Error
+	return suggestions, err
+}
+
+// GetTermSuggestion loads a suggestion by ID.
+func (b *Bot) GetTermSuggestion(id uint) (TermSuggestion, error) {
+	var suggestion TermSuggestion
+	err := b.DB.First(&suggestion, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return suggestion, fmt.Errorf("term suggestion #%d not found", id)
+	}
+	return suggestion, err
+}
+
+// ReviewTermSuggestion accepts or rejects a pending suggestion. Accepted
+// changes are made on behalf of the member who suggested them, so they are
+// credited in the term's history. Accepting an edit of a term whose
+// description changed since it was suggested would overwrite that change, so
+// the suggestion is marked stale instead.
+func (b *Bot) ReviewTermSuggestion(id uint, accept bool, reviewer Actor) (TermSuggestion, error) {
+	suggestion, err := b.GetTermSuggestion(id)
+	if err != nil {
+		return suggestion, err
+	}
+
+	status := TermSuggestionRejected
+	if accept {
+		status = TermSuggestionAccepted
+	}
+	// Claim the suggestion first so that two reviewers can't both apply it
+	result := b.DB.Model(&TermSuggestion{}).Where("id = ? AND status = ?", id, TermSuggestionPending).
+		Updates(map[string]interface{}{"status": status, "reviewed_by": reviewer.ID})
+	if result.Error != nil {
+		return suggestion, fmt.Errorf("error saving term suggestion review: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return suggestion, fmt.Errorf("term suggestion #%d was already reviewed", id)
+	}
+
+	if accept && suggestion.Action == TermActionEdit {
+		if current, err := b.FindTerm(suggestion.Term); err == nil && current.Description != suggestion.Previous {
+			status = TermSuggestionStale
+			if err := b.DB.Model(&suggestion).Update("status", status).Error; err != nil {
+				return suggestion, fmt.Errorf("error saving term suggestion review: %w", err)
+			}
+		}
+	}
+
+	if status == TermSuggestionAccepted {
+		// The suggester's name isn't stored, so the audit log mentions them by ID
+		suggester := Actor{ID: suggestion.SuggestedBy, GuildID: reviewer.GuildID}
+		if err := b.applyTermSuggestion(suggestion, suggester); err != nil {
+			if revertErr := b.DB.Model(&suggestion).Updates(map[string]interface{}{"status": TermSuggestionPending, "reviewed_by": ""}).Error; revertErr != nil {
+				b.GetLogger().WithError(revertErr).WithField("suggestion", id).Error("Error reopening term suggestion")
+			}
+			return suggestion, err
+		}
+	}
+
+	suggestion.Status = status
+	suggestion.ReviewedBy = reviewer.ID
+	b.audit(reviewer, "term.review", suggestion.Term, TermSuggestionPending, status)
+	b.creditTermSuggester(suggestion)
+	return suggestion, nil
+}
+
+func (b *Bot) applyTermSuggestion(suggestion TermSuggestion, suggester Actor) error {
+	switch suggestion.Action {
+	case TermActionAdd:
+		return b.AddTerm(suggestion.Term, suggestion.Description, suggester)
+	case TermActionEdit:
+		return b.EditTerm(suggestion.Term, suggestion.Description, suggester)
+	case TermActionRemove:
+		return b.RemoveTerm(suggestion.Term, suggester)
+	default:
+		return fmt.Errorf("unknown term suggestion action '%s'", suggestion.Action)
+	}
+}
+
+// termReviewChannel returns the channel suggested term changes are posted to.
+func (b *Bot) termReviewChannel() string {
+	if b.Config.Glossary.ReviewChannelID != "" {
+		return b.Config.Glossary.ReviewChannelID
+	}
+	return b.Config.Discord.AdminChannelID
+}
+
+// sendTermSuggestionReview posts a suggestion with accept and reject buttons
+// to the review channel.
+func (b *Bot) sendTermSuggestionReview(suggestion TermSuggestion) {
+	b.GetLogger().WithField("term", suggestion.Term).Info("Term change queued for review")
+	channelID := b.termReviewChannel()
+	if channelID == "" || b.Session == nil {
+		return
+	}
+
+	id := fmt.Sprint(suggestion.ID)
+	_, err := b.Session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{suggestion.ReviewEmbed()},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "Accept",
+					Style:    discordgo.SuccessButton,
+					CustomID: ComponentID(TermSuggestionComponent, TermSuggestionAccept, id),
+				},
+				discordgo.Button{
+					Label:    "Reject",
+					Style:    discordgo.DangerButton,
+					CustomID: ComponentID(TermSuggestionComponent, TermSuggestionReject, id),
+				},
+			}},
+		},
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", suggestion.Term).Error("Error posting term suggestion review")
+	}
+}
+
+// Summary describes the suggested change, e.g. "edit of term 'FC'".
+func (t TermSuggestion) Summary() string {
+	switch t.Action {
+	case TermActionAdd:
+		return fmt.Sprintf("new term '%s'", t.Term)
+	case TermActionRemove:
+		return fmt.Sprintf("removal of term '%s'", t.Term)
+	default:
+		return fmt.Sprintf("edit of term '%s'", t.Term)
+	}
+}
+
+// ReviewEmbed renders a suggestion for the review queue. Edits show what
+// they change in the description.
+func (t TermSuggestion) ReviewEmbed() *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("📝 Term suggestion #%d awaiting review", t.ID),
+		Timestamp: t.CreatedAt.Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Change", Value: t.Action, Inline: true},
+			{Name: "Term", Value: t.Term, Inline: true},
+			{Name: "Suggested by", Value: fmt.Sprintf("<@%s>", t.SuggestedBy), Inline: true},
+		},
+	}
+	switch t.Action {
+	case TermActionAdd:
+		embed.Description = Truncate(t.Description, 4000)
+	case TermActionEdit:
+		diff := strings.Join(DiffLines(t.Previous, t.Description), "\n")
+		embed.Description = fmt.Sprintf("```diff\n%s\n```", Truncate(diff, 3900))
+	case TermActionRemove:
+		embed.Description = Truncate(t.Previous, 4000)
+	}
+	return embed
+}
+
+// creditTermSuggester tells the member who suggested a change how the review
+// went.
+func (b *Bot) creditTermSuggester(suggestion TermSuggestion) {
+	if suggestion.ChannelID == "" || b.Session == nil {
+		return
+	}
+	message := fmt.Sprintf("<@%s> your suggested %s was not accepted.", suggestion.SuggestedBy, suggestion.Summary())
+	switch suggestion.Status {
+	case TermSuggestionAccepted:
+		message = fmt.Sprintf("✓ <@%s> thanks! Your suggested %s was accepted.", suggestion.SuggestedBy, suggestion.Summary())
+	case TermSuggestionStale:
+		message = fmt.Sprintf("<@%s> your suggested %s was not applied because the term changed in the meantime. Please suggest it again.",
+			suggestion.SuggestedBy, suggestion.Summary())
+	}
+	b.SendMessage(b.Session, suggestion.ChannelID, message)
+}
@@ -0,0 +1,52 @@
+// File: internal/bot/term_suggestions_test.go
+
+package bot
+
+import "testing"
+
+func TestReviewStaleTermSuggestion(t *testing.T) {
+	b := newTestBot(t)
+	editor := Actor{ID: "editor"}
+	member := Actor{ID: "member"}
+
+	if err := b.AddTerm("Furnace", "Main building.", editor); err != nil {
+		t.Fatal(err)
+	}
+	first, err := b.SuggestTermChange(TermActionEdit, "Furnace", "The main building.", "", member)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := b.SuggestTermChange(TermActionEdit, "Furnace", "Upgrade it first.", "", Actor{ID: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		id         uint
+		wantStatus string
+		wantDesc   string
+	}{
+		{"accepted", first.ID, TermSuggestionAccepted, "The main building."},
+		{"term changed since", second.ID, TermSuggestionStale, "The main building."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suggestion, err := b.ReviewTermSuggestion(tt.id, true, editor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if suggestion.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", suggestion.Status, tt.wantStatus)
+			}
+			stored, err := b.GetTermSuggestion(tt.id)
+			if err != nil || stored.Status != tt.wantStatus {
+				t.Errorf("stored status = %s, %v, want %s", stored.Status, err, tt.wantStatus)
+			}
+			term, err := b.FindTerm("Furnace")
+			if err != nil || term.Description != tt.wantDesc {
+				t.Errorf("description = %q, %v, want %q", term.Description, err, tt.wantDesc)
+			}
+		})
+	}
+}
@@ -3,95 +3,274 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// UncategorizedTerms is the category shown for terms without one.
+const UncategorizedTerms = "Uncategorized"
+
+// ErrTermNotFound is returned when no term has the requested title or alias.
+var ErrTermNotFound = errors.New("term not found")
+
+// NormalizeTermTitle trims a term title or alias and collapses the spaces
+// between its words.
+func NormalizeTermTitle(title string) string {
+	return strings.Join(strings.Fields(title), " ")
+}
+
+// AliasList returns the aliases of a term.
+func (t Term) AliasList() []string {
+	aliases := make([]string, len(t.Aliases))
+	for i, alias := range t.Aliases {
+		aliases[i] = alias.Alias
+	}
+	return aliases
+}
+
+// DisplayCategory returns the category of a term, or UncategorizedTerms.
+func (t Term) DisplayCategory() string {
+	if t.Category == "" {
+		return UncategorizedTerms
+	}
+	return t.Category
+}
+
+// FindTerm looks up a term by its title or one of its aliases, ignoring case.
+func (b *Bot) FindTerm(name string) (Term, error) {
+	name = strings.ToLower(NormalizeTermTitle(name))
+
+	var t Term
+	result := b.DB.Preload("Aliases").Where("LOWER(term) = ?", name).First(&t)
+	if result.Error == nil {
+		return t, nil
+	}
+	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return t, result.Error
+	}
+
+	var alias TermAlias
+	result = b.DB.Where("LOWER(alias) = ?", name).First(&alias)
+	if result.Error == nil {
+		result = b.DB.Preload("Aliases").First(&t, alias.TermID)
+	}
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return t, fmt.Errorf("%w: '%s'", ErrTermNotFound, name)
+	}
+	return t, result.Error
+}
+
+// checkTermNameFree returns an error when name is already the title or an
+// alias of a term.
+func (b *Bot) checkTermNameFree(name string) error {
+	existing, err := b.FindTerm(name)
+	if err == nil {
+		if strings.EqualFold(existing.Term, name) {
+			return fmt.Errorf("term '%s' already exists", existing.Term)
+		}
+		return fmt.Errorf("'%s' is already an alias of term '%s'", name, existing.Term)
+	}
+	if errors.Is(err, ErrTermNotFound) {
+		return nil
+	}
+	return err
+}
+
 // AddTerm adds a new term and its description to the database.
-func (b *Bot) AddTerm(term, description string) error {
+func (b *Bot) AddTerm(term, description string, actor Actor) error {
+	term = NormalizeTermTitle(term)
+	if err := b.checkTermNameFree(term); err != nil {
+		return err
+	}
+	if removed, err := b.findRemovedTerm(term); err == nil {
+		return fmt.Errorf("term '%s' was removed, restore it instead", removed.Term)
+	}
+
 	newTerm := Term{
 		Term:        term,
 		Description: description,
+		AuthorID:    actor.ID,
 	}
-	result := b.DB.Create(&newTerm)
-	if result.Error != nil {
-		log.Printf("Error adding term '%s': %v", term, result.Error)
-		return result.Error
+	err := b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newTerm).Error; err != nil {
+			return err
+		}
+		return recordTermRevision(tx, newTerm.ID, TermActionAdd, "", actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Error("Error adding term")
+		return err
 	}
-	log.Printf("Successfully added term '%s'", term)
+	b.audit(actor, "term.add", term, "", description)
+	b.GetLogger().WithField("term", term).Info("Term added")
 	return nil
 }
 
 // EditTerm edits the description of an existing term.
-func (b *Bot) EditTerm(term, newDescription string) error {
-	var existingTerm Term
-	result := b.DB.Where("term = ?", term).First(&existingTerm)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			log.Printf("Term '%s' not found for editing", term)
-			return fmt.Errorf("term '%s' not found", term)
-		}
-		log.Printf("Error finding term '%s' for editing: %v", term, result.Error)
-		return result.Error
+func (b *Bot) EditTerm(term, newDescription string, actor Actor) error {
+	existingTerm, err := b.FindTerm(term)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Debug("Term to edit not found")
+		return err
 	}
 
+	oldDescription := existingTerm.Description
 	existingTerm.Description = newDescription
-	saveResult := b.DB.Save(&existingTerm)
-	if saveResult.Error != nil {
-		log.Printf("Error saving term '%s': %v", term, saveResult.Error)
-		return saveResult.Error
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Save(&existingTerm).Error; err != nil {
+			return err
+		}
+		return recordTermRevision(tx, existingTerm.ID, TermActionEdit, "", actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Error("Error saving term")
+		return err
 	}
-	log.Printf("Successfully updated term '%s'", term)
+	b.audit(actor, "term.edit", existingTerm.Term, oldDescription, newDescription)
+	b.GetLogger().WithField("term", existingTerm.Term).Info("Term updated")
 	return nil
 }
 
-// RemoveTerm removes a term from the database.
-func (b *Bot) RemoveTerm(term string) error {
-	result := b.DB.Where("term = ?", term).Delete(&Term{})
+// RemoveTerm removes a term and its aliases from the database. The term is
+// soft-deleted and its last revision keeps the aliases, so RestoreTerm can
+// bring both back.
+func (b *Bot) RemoveTerm(term string, actor Actor) error {
+	existingTerm, err := b.FindTerm(term)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Debug("Term to remove not found")
+		return err
+	}
+
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := recordTermRevision(tx, existingTerm.ID, TermActionRemove, "", actor.ID); err != nil {
+			return err
+		}
+		if err := tx.Where("term_id = ?", existingTerm.ID).Delete(&TermAlias{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&existingTerm).Error
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Error("Error removing term")
+		return err
+	}
+	b.audit(actor, "term.remove", existingTerm.Term, existingTerm.Description, "")
+
+	b.GetLogger().WithField("term", existingTerm.Term).Info("Term removed")
+	return nil
+}
+
+// AddTermAlias lets a term also be looked up by alias.
+func (b *Bot) AddTermAlias(term, alias string, actor Actor) (Term, error) {
+	existingTerm, err := b.FindTerm(term)
+	if err != nil {
+		return existingTerm, err
+	}
+	alias = NormalizeTermTitle(alias)
+	if err := b.checkTermNameFree(alias); err != nil {
+		return existingTerm, err
+	}
+
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&TermAlias{TermID: existingTerm.ID, Alias: alias}).Error; err != nil {
+			return err
+		}
+		return recordTermRevision(tx, existingTerm.ID, TermActionAlias, alias, actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", existingTerm.Term).WithField("alias", alias).Error("Error adding alias")
+		return existingTerm, err
+	}
+	b.audit(actor, "term.alias", existingTerm.Term, "", alias)
+	b.GetLogger().WithField("term", existingTerm.Term).WithField("alias", alias).Info("Alias added")
+	return existingTerm, nil
+}
+
+// RemoveTermAlias removes an alias and returns the term it belonged to.
+func (b *Bot) RemoveTermAlias(alias string, actor Actor) (Term, error) {
+	var existing TermAlias
+	alias = NormalizeTermTitle(alias)
+	result := b.DB.Where("LOWER(alias) = ?", strings.ToLower(alias)).First(&existing)
 	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			log.Printf("Term '%s' not found for removal", term)
-			return fmt.Errorf("term '%s' not found", term)
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return Term{}, fmt.Errorf("alias '%s' not found", alias)
 		}
-		log.Printf("Error removing term '%s': %v", term, result.Error)
-		return result.Error
+		return Term{}, result.Error
 	}
 
-	if result.RowsAffected == 0 {
-		log.Printf("No term found with name '%s' to remove", term)
-		return fmt.Errorf("term '%s' not found", term)
+	var t Term
+	if err := b.DB.First(&t, existing.TermID).Error; err != nil {
+		return t, err
 	}
+	err := b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&existing).Error; err != nil {
+			return err
+		}
+		return recordTermRevision(tx, t.ID, TermActionUnalias, existing.Alias, actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("alias", alias).Error("Error removing alias")
+		return t, err
+	}
+	b.audit(actor, "term.unalias", t.Term, existing.Alias, "")
+	b.GetLogger().WithField("term", t.Term).WithField("alias", existing.Alias).Info("Alias removed")
+	return t, nil
+}
 
-	log.Printf("Successfully removed term '%s'", term)
+// SetTermCategory moves a term into a category. An empty category leaves the
+// term uncategorized.
+func (b *Bot) SetTermCategory(term, category string, actor Actor) error {
+	existingTerm, err := b.FindTerm(term)
+	if err != nil {
+		return err
+	}
+	oldCategory := existingTerm.Category
+	category = NormalizeTermTitle(category)
+	err = b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingTerm).Update("category", category).Error; err != nil {
+			return err
+		}
+		return recordTermRevision(tx, existingTerm.ID, TermActionCategory, "", actor.ID)
+	})
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", existingTerm.Term).Error("Error setting term category")
+		return err
+	}
+	b.audit(actor, "term.category", existingTerm.Term, oldCategory, category)
+	b.GetLogger().WithField("term", existingTerm.Term).WithField("category", category).Info("Term category set")
 	return nil
 }
 
-// ListTerms lists all terms in the database.
-func (b *Bot) ListTerms() ([]Term, error) {
+// ListTerms lists the terms in the database ordered by category and title.
+// A non-empty category lists only the terms in that category.
+func (b *Bot) ListTerms(category string) ([]Term, error) {
 	var terms []Term
-	result := b.DB.Order("term").Find(&terms)
+	query := b.DB.Preload("Aliases").Order("category").Order("LOWER(term)")
+	switch {
+	case strings.EqualFold(category, UncategorizedTerms):
+		query = query.Where("category = '' OR category IS NULL")
+	case category != "":
+		query = query.Where("LOWER(category) = ?", strings.ToLower(NormalizeTermTitle(category)))
+	}
+	result := query.Find(&terms)
 	if result.Error != nil {
-		log.Printf("Error listing terms: %v", result.Error)
+		b.GetLogger().WithError(result.Error).Error("Error listing terms")
 		return nil, result.Error
 	}
-	log.Printf("Successfully listed %d terms", len(terms))
+	b.GetLogger().Debugf("Listed %d terms", len(terms))
 	return terms, nil
 }
 
 // GetTermDescription gets the description of a given term.
 func (b *Bot) GetTermDescription(term string) (string, error) {
-	var t Term
-	result := b.DB.Where("term = ?", term).First(&t)
-	if result.Error != nil {
-		if result.Error == gorm.ErrRecordNotFound {
-			log.Printf("Term '%s' not found when getting description", term)
-			return "", fmt.Errorf("term '%s' not found", term)
-		}
-		log.Printf("Error retrieving term '%s' description: %v", term, result.Error)
-		return "", result.Error
+	t, err := b.FindTerm(term)
+	if err != nil {
+		b.GetLogger().WithError(err).WithField("term", term).Debug("Term description not found")
+		return "", err
 	}
-	log.Printf("Successfully retrieved description for term '%s'", term)
+	b.GetLogger().WithField("term", term).Debug("Term description retrieved")
 	return t.Description, nil
 }
@@ -0,0 +1,199 @@
+// File: internal/bot/user_notify.go
+
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"gorm.io/gorm"
+)
+
+// maxDMFailures is how many DMs in a row may fail before DMs to a user are
+// paused. Closed DMs pause them straight away.
+const maxDMFailures = 3
+
+// dmQueueSize is how many DMs may wait for delivery before new ones are
+// dropped.
+const dmQueueSize = 1000
+
+// queuedDM is a DM waiting to be sent by the DM worker. kind names the DM in
+// logs.
+type queuedDM struct {
+	userID  string
+	message *discordgo.MessageSend
+	kind    string
+}
+
+// GetUserNotificationSettings returns a user's notification preferences,
+// or the defaults (DMs off) for users without stored preferences.
+func (b *Bot) GetUserNotificationSettings(discordID string) UserNotificationSettings {
+	var settings UserNotificationSettings
+	err := b.DB.Where("discord_id = ?", discordID).First(&settings).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			b.GetLogger().WithError(err).WithField("user", discordID).Error("Error loading user notification settings")
+		}
+		return UserNotificationSettings{DiscordID: discordID}
+	}
+	return settings
+}
+
+// SetDMNotifications turns DM notifications on or off for actor. Turning
+// them on also resumes DMs that were paused after delivery failures.
+func (b *Bot) SetDMNotifications(enabled bool, actor Actor) error {
+	settings := b.GetUserNotificationSettings(actor.ID)
+	before := settings.DMEnabled
+	settings.DMEnabled = enabled
+	if enabled {
+		settings.DMFailures = 0
+		settings.DMPaused = false
+		settings.LastDMError = ""
+	}
+	if err := b.DB.Save(&settings).Error; err != nil {
+		return fmt.Errorf("error saving notification settings: %w", err)
+	}
+	b.audit(actor, "notify.dm", actor.ID, OnOff(before), OnOff(enabled))
+	return nil
+}
+
+// dmSubscribers returns the users who opted into DM notifications and whose
+// DMs aren't paused.
+func (b *Bot) dmSubscribers() ([]string, error) {
+	var userIDs []string
+	err := b.DB.Model(&UserNotificationSettings{}).
+		Where("dm_enabled = ? AND dm_paused = ?", true, false).
+		Pluck("discord_id", &userIDs).Error
+	return userIDs, err
+}
+
+// sendDM sends a direct message and tracks the outcome, pausing DMs to users
+// who have closed their DMs or whose DMs keep failing.
+func (b *Bot) sendDM(userID string, message *discordgo.MessageSend) error {
+	if b.Session == nil {
+		return fmt.Errorf("discord is disabled")
+	}
+	settings := b.GetUserNotificationSettings(userID)
+	if settings.DMPaused {
+		return fmt.Errorf("DMs to %s are paused after %d failures", userID, settings.DMFailures)
+	}
+
+	channel, err := b.Session.UserChannelCreate(userID)
+	if err == nil {
+		_, err = b.Session.ChannelMessageSendComplex(channel.ID, message)
+	}
+	b.recordDMResult(userID, err)
+	if err != nil {
+		return fmt.Errorf("error sending DM to %s: %w", userID, err)
+	}
+	return nil
+}
+
+// recordDMResult updates a user's DM delivery status. The failure count is
+// updated in the database rather than from a loaded copy, so that DMs sent
+// at the same time don't overwrite each other's counts.
+func (b *Bot) recordDMResult(userID string, err error) {
+	if err == nil {
+		if err := b.DB.Model(&UserNotificationSettings{}).Where("discord_id = ? AND dm_failures > 0", userID).
+			Updates(map[string]interface{}{"dm_failures": 0, "last_dm_error": ""}).Error; err != nil {
+			b.GetLogger().WithError(err).WithField("user", userID).Error("Error saving DM delivery status")
+		}
+		return
+	}
+
+	var restErr *discordgo.RESTError
+	closed := errors.As(err, &restErr) && restErr.Message != nil &&
+		restErr.Message.Code == discordgo.ErrCodeCannotSendMessagesToThisUser
+	failure := map[string]interface{}{
+		"dm_failures":      gorm.Expr("dm_failures + 1"),
+		"last_dm_error":    err.Error(),
+		"last_dm_error_at": time.Now(),
+	}
+	var paused int64
+	txErr := b.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&UserNotificationSettings{}).Where("discord_id = ?", userID).Updates(failure).Error; err != nil {
+			return err
+		}
+		pause := tx.Model(&UserNotificationSettings{}).Where("discord_id = ? AND dm_paused = ?", userID, false)
+		if !closed {
+			pause = pause.Where("dm_failures >= ?", maxDMFailures)
+		}
+		result := pause.Update("dm_paused", true)
+		paused = result.RowsAffected
+		return result.Error
+	})
+	if txErr != nil {
+		b.GetLogger().WithError(txErr).WithField("user", userID).Error("Error saving DM delivery status")
+		return
+	}
+	if paused > 0 {
+		b.GetLogger().WithError(err).WithField("user", userID).Warn("Pausing DM notifications")
+	}
+}
+
+// queueDM hands a DM to the DM worker, so that callers such as the deploy
+// loop don't wait for Discord. DMs are dropped when the queue is full.
+func (b *Bot) queueDM(userID string, message *discordgo.MessageSend, kind string) {
+	select {
+	case b.dms <- queuedDM{userID: userID, message: message, kind: kind}:
+	default:
+		b.GetLogger().WithField("user", userID).Warnf("DM queue is full, dropping %s DM", kind)
+	}
+}
+
+// deliverDMs sends queued DMs one at a time until the bot shuts down.
+func (b *Bot) deliverDMs() {
+	for {
+		select {
+		case dm := <-b.dms:
+			if err := b.sendDM(dm.userID, dm.message); err != nil {
+				b.GetLogger().WithError(err).WithField("user", dm.userID).Warnf("Error sending %s DM", dm.kind)
+			}
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// dmNewCodes queues each new code for every DM subscriber.
+func (b *Bot) dmNewCodes(codes []DiscoveredCode) {
+	userIDs, err := b.dmSubscribers()
+	if err != nil {
+		b.GetLogger().WithError(err).Error("Error loading DM subscribers")
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	for _, code := range codes {
+		n := b.newCodeNotification(code)
+		for _, userID := range userIDs {
+			b.queueDM(userID, notificationMessage("", n), "new code")
+		}
+	}
+}
+
+// dmRedemptionResult tells a subscribed user how a deployed code was redeemed
+// for their account.
+func (b *Bot) dmRedemptionResult(code string, result DeployResult) {
+	if !b.GetUserNotificationSettings(result.DiscordID).DMEnabled {
+		return
+	}
+
+	var content string
+	switch {
+	case result.Err != nil && result.Message == "":
+		content = fmt.Sprintf("⚠️ Gift code `%s` couldn't be redeemed for player %s: %v\nTry again with `%s`.",
+			code, result.PlayerID, result.Err, b.WithPrefix("!giftcode redeem "+code))
+	case result.Success:
+		content = fmt.Sprintf("✓ Gift code `%s` was redeemed for player %s: %s", code, result.PlayerID, result.Message)
+	default:
+		// The API answered, so retrying won't change the outcome
+		content = fmt.Sprintf("𐄂 Gift code `%s` could not be redeemed for player %s: %s\nIt won't be retried.", code, result.PlayerID, result.Message)
+	}
+
+	b.queueDM(result.DiscordID, &discordgo.MessageSend{Content: content}, "redemption")
+}
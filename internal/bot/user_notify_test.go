@@ -0,0 +1,50 @@
+// File: internal/bot/user_notify_test.go
+
+package bot
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRecordDMResult(t *testing.T) {
+	b := newTestBot(t)
+
+	failed := errors.New("gateway timeout")
+	closed := &discordgo.RESTError{
+		Response: &http.Response{StatusCode: http.StatusForbidden},
+		Message:  &discordgo.APIErrorMessage{Code: discordgo.ErrCodeCannotSendMessagesToThisUser},
+	}
+
+	tests := []struct {
+		name         string
+		results      []error
+		wantFailures int
+		wantPaused   bool
+	}{
+		{"delivered", []error{nil}, 0, false},
+		{"one failure", []error{failed}, 1, false},
+		{"failures in a row", []error{failed, failed, failed}, 3, true},
+		{"delivery resets failures", []error{failed, failed, nil, failed}, 1, false},
+		{"closed DMs", []error{closed}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := tt.name
+			if err := b.DB.Create(&UserNotificationSettings{DiscordID: userID, DMEnabled: true}).Error; err != nil {
+				t.Fatal(err)
+			}
+			for _, err := range tt.results {
+				b.recordDMResult(userID, err)
+			}
+			settings := b.GetUserNotificationSettings(userID)
+			if settings.DMFailures != tt.wantFailures || settings.DMPaused != tt.wantPaused {
+				t.Errorf("failures = %d, paused = %v, want %d, %v",
+					settings.DMFailures, settings.DMPaused, tt.wantFailures, tt.wantPaused)
+			}
+		})
+	}
+}
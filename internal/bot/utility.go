@@ -6,63 +6,35 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"sync"
 	"time"
 
-	"github.com/patrickmn/go-cache"
-	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
-var (
-	cooldownCache *cache.Cache
-	cacheMutex    sync.Mutex
-	utilLogger    *logrus.Logger
-	logger        *logrus.Logger
-	loggerOnce    sync.Once
-)
-
-func init() {
-	cooldownCache = cache.New(5*time.Minute, 10*time.Minute)
-}
-
-func SetUtilLogger(logger *logrus.Logger) {
-	utilLogger = logger
-}
-
-// // TODO: is GetLogger correct?
-func GetLogger() *logrus.Entry {
-	loggerOnce.Do(func() {
-		logger = logrus.New()
-		logger.SetLevel(logrus.DebugLevel)
-	})
-	return logger.WithField("service", "the-keeper")
-}
-
 func ParseArguments(input string) []string {
 	return strings.Fields(input)
 }
 
-func CheckCooldown(userID, command, cooldownStr string) bool {
+func (b *Bot) CheckCooldown(userID, command, cooldownStr string) bool {
 	if cooldownStr == "" {
 		return true
 	}
 
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
+	b.cooldownMutex.Lock()
+	defer b.cooldownMutex.Unlock()
 
 	key := userID + ":" + command
-	if _, found := cooldownCache.Get(key); found {
+	if _, found := b.cooldowns.Get(key); found {
 		return false
 	}
 
 	duration, err := time.ParseDuration(cooldownStr)
 	if err != nil {
-		utilLogger.Errorf("Invalid cooldown duration: %v", err)
+		b.GetLogger().Errorf("Invalid cooldown duration: %v", err)
 		return true
 	}
 
-	cooldownCache.Set(key, true, duration)
+	b.cooldowns.Set(key, true, duration)
 	return true
 }
 
@@ -102,6 +74,35 @@ func (b *Bot) GetAllPlayerIDs() (map[string]string, error) {
 	return playerIDs, nil
 }
 
+// AddPlayerID links a player ID to a Discord user.
+func (b *Bot) AddPlayerID(discordID, playerID string, actor Actor) error {
+	if err := b.DB.Create(&Player{DiscordID: discordID, PlayerID: playerID}).Error; err != nil {
+		return err
+	}
+	b.audit(actor, "id.add", discordID, "", playerID)
+	return nil
+}
+
+// EditPlayerID changes the player ID linked to a Discord user.
+func (b *Bot) EditPlayerID(discordID, playerID string, actor Actor) error {
+	oldPlayerID, _ := b.GetPlayerID(discordID)
+	if err := b.DB.Model(&Player{}).Where("discord_id = ?", discordID).Update("player_id", playerID).Error; err != nil {
+		return err
+	}
+	b.audit(actor, "id.edit", discordID, oldPlayerID, playerID)
+	return nil
+}
+
+// RemovePlayerID unlinks the player ID of a Discord user.
+func (b *Bot) RemovePlayerID(discordID string, actor Actor) error {
+	oldPlayerID, _ := b.GetPlayerID(discordID)
+	if err := b.DB.Where("discord_id = ?", discordID).Delete(&Player{}).Error; err != nil {
+		return err
+	}
+	b.audit(actor, "id.remove", discordID, oldPlayerID, "")
+	return nil
+}
+
 // ListPlayers lists all players in the database.
 func (b *Bot) ListPlayers() ([]Player, error) {
 	var players []Player
@@ -111,3 +112,18 @@ func (b *Bot) ListPlayers() ([]Player, error) {
 	}
 	return players, nil
 }
+
+// DumpDatabase returns every term and player for !dbdump, recording in the
+// audit log that they were read.
+func (b *Bot) DumpDatabase(actor Actor) ([]Term, []Player, error) {
+	b.audit(actor, "dbdump", "", "", "")
+	terms, err := b.ListTerms("")
+	if err != nil {
+		return nil, nil, fmt.Errorf("error listing terms: %w", err)
+	}
+	players, err := b.ListPlayers()
+	if err != nil {
+		return terms, nil, fmt.Errorf("error listing players: %w", err)
+	}
+	return terms, players, nil
+}